@@ -0,0 +1,271 @@
+package req
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imroc/req/v3/internal/header"
+)
+
+const (
+	awsV4Algorithm      = "AWS4-HMAC-SHA256"
+	awsV4TimeFormat     = "20060102T150405Z"
+	awsV4DateFormat     = "20060102"
+	awsV4UnsignedBody   = "UNSIGNED-PAYLOAD"
+	awsV4MaxPresignTime = 7 * 24 * time.Hour
+)
+
+// awsV4IgnoredHeaders are not signed because they may be modified by proxies.
+var awsV4IgnoredHeaders = map[string]bool{
+	"authorization":   true,
+	"user-agent":      true,
+	"x-amzn-trace-id": true,
+	"expect":          true,
+}
+
+// awsV4Signer signs the requests with AWS Signature Version 4, see
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv4.html
+type awsV4Signer struct {
+	region       string
+	service      string
+	accessKeyID  string
+	secretKey    string
+	sessionToken string
+}
+
+// awsV4URIEncode encodes s as described in the AWS Signature Version 4, every
+// byte except the unreserved characters is percent-encoded with upper-case
+// hex, and the "/" is kept if encodeSlash is false.
+func awsV4URIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func awsV4HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func awsV4Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *awsV4Signer) scope(t time.Time) string {
+	return strings.Join([]string{t.Format(awsV4DateFormat), s.region, s.service, "aws4_request"}, "/")
+}
+
+// canonicalPath returns the canonical URI, which is URI-encoded twice except
+// for S3.
+func (s *awsV4Signer) canonicalPath(req *http.Request) string {
+	path := req.URL.Path
+	if path == "" {
+		return "/"
+	}
+	path = awsV4URIEncode(path, false)
+	if s.service != "s3" {
+		path = awsV4URIEncode(path, false)
+	}
+	return path
+}
+
+// awsV4CanonicalQuery returns the canonical query string, the parameters are
+// sorted by the encoded name and then by the encoded value.
+func awsV4CanonicalQuery(req *http.Request) string {
+	type param struct{ key, value string }
+	query := req.URL.Query()
+	params := make([]param, 0, len(query))
+	for k, vs := range query {
+		for _, v := range vs {
+			params = append(params, param{awsV4URIEncode(k, true), awsV4URIEncode(v, true)})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].key != params[j].key {
+			return params[i].key < params[j].key
+		}
+		return params[i].value < params[j].value
+	})
+	pairs := make([]string, len(params))
+	for i, p := range params {
+		pairs[i] = p.key + "=" + p.value
+	}
+	return strings.Join(pairs, "&")
+}
+
+// canonicalHeaders returns the canonical headers and the signed headers, the
+// host and all headers except awsV4IgnoredHeaders are signed.
+func awsV4CanonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	values := map[string][]string{"host": {host}}
+	for k, vs := range req.Header {
+		name := strings.ToLower(k)
+		// the host is taken from req.Host, which overrides the Host header.
+		if name == "host" || awsV4IgnoredHeaders[name] || k == header.HeaderOderKey || k == header.PseudoHeaderOderKey {
+			continue
+		}
+		values[name] = append(values[name], vs...)
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		vs := values[name]
+		for i, v := range vs {
+			vs[i] = strings.Join(strings.Fields(v), " ")
+		}
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(vs, ","))
+		b.WriteByte('\n')
+	}
+	return b.String(), strings.Join(names, ";")
+}
+
+func (s *awsV4Signer) signature(req *http.Request, payloadHash string, t time.Time) (signature, signedHeaders string) {
+	canonicalHeaders, signedHeaders := awsV4CanonicalHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s.canonicalPath(req),
+		awsV4CanonicalQuery(req),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		awsV4Algorithm,
+		t.Format(awsV4TimeFormat),
+		s.scope(t),
+		awsV4Hash([]byte(canonicalRequest)),
+	}, "\n")
+	key := awsV4HMAC([]byte("AWS4"+s.secretKey), t.Format(awsV4DateFormat))
+	key = awsV4HMAC(key, s.region)
+	key = awsV4HMAC(key, s.service)
+	key = awsV4HMAC(key, "aws4_request")
+	return hex.EncodeToString(awsV4HMAC(key, stringToSign)), signedHeaders
+}
+
+// sign sets the "X-Amz-Date" and "Authorization" headers of req (and the
+// "X-Amz-Security-Token" header if the session token is set), body is the
+// raw request body, and the streamed body is not signed.
+func (s *awsV4Signer) sign(req *http.Request, body []byte, t time.Time) {
+	t = t.UTC()
+	payloadHash := awsV4Hash(body)
+	if body == nil && req.Body != nil && req.Body != http.NoBody {
+		payloadHash = awsV4UnsignedBody
+	}
+	req.Header.Set("X-Amz-Date", t.Format(awsV4TimeFormat))
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	if s.service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+	signature, signedHeaders := s.signature(req, payloadHash, t)
+	req.Header.Set(header.Authorization, fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsV4Algorithm, s.accessKeyID, s.scope(t), signedHeaders, signature))
+}
+
+// presign returns the presigned URL of req which expires after ttl, only the
+// host header is signed, and the body is not signed.
+func (s *awsV4Signer) presign(req *http.Request, ttl time.Duration, t time.Time) string {
+	t = t.UTC()
+	u := *req.URL
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", awsV4Algorithm)
+	query.Set("X-Amz-Credential", s.accessKeyID+"/"+s.scope(t))
+	query.Set("X-Amz-Date", t.Format(awsV4TimeFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(ttl/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if s.sessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+	presignReq := &http.Request{Method: req.Method, URL: &u, Host: req.Host, Header: make(http.Header)}
+	signature, _ := s.signature(presignReq, awsV4UnsignedBody, t)
+	u.RawQuery += "&X-Amz-Signature=" + signature
+	return u.String()
+}
+
+// SetCommonAWSV4Auth signs every request fired from the client with AWS
+// Signature Version 4 right before it is sent, which sets the "X-Amz-Date"
+// and "Authorization" headers (and "X-Amz-Content-Sha256" for S3). The host
+// and all headers except "Authorization", "User-Agent", "X-Amzn-Trace-Id" and
+// "Expect" are signed.
+//
+// Note the body hash is computed from the in-memory request body, so the
+// streamed body (e.g. set by io.Reader) is signed as "UNSIGNED-PAYLOAD" which
+// is only accepted by S3.
+//
+// For Example:
+//
+//	client.SetCommonAWSV4Auth("us-east-1", "execute-api", accessKeyID, secretKey)
+func (c *Client) SetCommonAWSV4Auth(region, service, accessKeyID, secretKey string) *Client {
+	return c.SetCommonAWSV4AuthWithSession(region, service, accessKeyID, secretKey, "")
+}
+
+// SetCommonAWSV4AuthWithSession is similar to SetCommonAWSV4Auth, but uses
+// the temporary credentials with the session token (e.g. from AWS STS), which
+// is sent in the "X-Amz-Security-Token" header.
+func (c *Client) SetCommonAWSV4AuthWithSession(region, service, accessKeyID, secretKey, sessionToken string) *Client {
+	c.awsV4Signer = &awsV4Signer{
+		region:       region,
+		service:      service,
+		accessKeyID:  accessKeyID,
+		secretKey:    secretKey,
+		sessionToken: sessionToken,
+	}
+	return c
+}
+
+// GetPresignedURL returns the URL of the request presigned with AWS Signature
+// Version 4 using the credentials set by Client.SetCommonAWSV4Auth, which can
+// be used to access the resource without credentials (e.g. download the S3
+// object by browser) until it expires after ttl (at most 7 days). The method
+// is GET if it is not set by Request.SetMethod, and only the host is signed.
+//
+// For Example:
+//
+//	url, err := client.R().SetURL("https://bucket.s3.amazonaws.com/a.txt").GetPresignedURL(time.Hour)
+func (r *Request) GetPresignedURL(ttl time.Duration) (string, error) {
+	signer := r.client.awsV4Signer
+	if signer == nil {
+		return "", errors.New("aws v4 auth is not set, see Client.SetCommonAWSV4Auth")
+	}
+	if ttl < time.Second || ttl > awsV4MaxPresignTime {
+		return "", fmt.Errorf("invalid presigned url expiration %v, must be between 1s and 7 days", ttl)
+	}
+	if err := parseRequestURL(r.client, r); err != nil {
+		return "", err
+	}
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req := &http.Request{Method: method, URL: r.URL, Host: r.getHeader("Host")}
+	return signer.presign(req, ttl, time.Now()), nil
+}
@@ -0,0 +1,208 @@
+package req
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedResponse is the response stored in the CacheStore.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// ETag returns the "ETag" header of the cached response.
+func (cr *CachedResponse) ETag() string {
+	return cr.Header.Get("ETag")
+}
+
+// LastModified returns the "Last-Modified" header of the cached response.
+func (cr *CachedResponse) LastModified() string {
+	return cr.Header.Get("Last-Modified")
+}
+
+// CacheStore is the storage of the cached responses, it must be safe
+// for concurrent use.
+type CacheStore interface {
+	// Get returns the cached response of the key, and whether it exists.
+	Get(key string) (*CachedResponse, bool)
+	// Set stores the response with the key, ttl <= 0 means the response
+	// never expires.
+	Set(key string, resp *CachedResponse, ttl time.Duration)
+}
+
+type inMemoryCacheEntry struct {
+	key      string
+	resp     *CachedResponse
+	expireAt time.Time
+}
+
+type inMemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	entries    map[string]*list.Element
+}
+
+// NewInMemoryCache creates a CacheStore which stores responses in memory,
+// the least recently used response is evicted when the number of entries
+// exceeds maxEntries, maxEntries <= 0 means no limit.
+func NewInMemoryCache(maxEntries int) CacheStore {
+	return &inMemoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (c *inMemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*inMemoryCacheEntry)
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		c.ll.Remove(e)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return entry.resp, true
+}
+
+func (c *inMemoryCache) Set(key string, resp *CachedResponse, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &inMemoryCacheEntry{key: key, resp: resp}
+	if ttl > 0 {
+		entry.expireAt = time.Now().Add(ttl)
+	}
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.ll.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.ll.PushFront(entry)
+	if c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*inMemoryCacheEntry).key)
+	}
+}
+
+// cacheKeyHeaders are the request headers which may change the response,
+// and are included in the cache key.
+var cacheKeyHeaders = []string{"Accept", "Accept-Encoding", "Accept-Language", "Authorization", "Cookie"}
+
+// cacheKey computes the cache key from the method, url and the headers
+// which may change the response.
+func cacheKey(r *Request) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method)
+	io.WriteString(h, "\n")
+	io.WriteString(h, r.URL.String())
+	for _, name := range cacheKeyHeaders {
+		io.WriteString(h, "\n")
+		io.WriteString(h, strings.Join(r.Headers.Values(name), ","))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheTTL returns the ttl of the response according to the "Cache-Control"
+// header, false means the response should not be cached.
+func cacheTTL(resp *http.Response) (time.Duration, bool) {
+	var ttl time.Duration
+	for _, directive := range strings.Split(resp.Header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == "no-store" {
+			return 0, false
+		}
+		if v, ok := strings.CutPrefix(directive, "max-age="); ok {
+			if seconds, err := strconv.ParseInt(v, 10, 64); err == nil && seconds > 0 {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return ttl, true
+}
+
+// cacheRoundTrip revalidates the cached response with the "If-None-Match"
+// and "If-Modified-Since" headers, returns the cached response if the server
+// responds with 304, and stores the successful response which has the
+// "ETag" or "Last-Modified" header.
+func cacheRoundTrip(rt RoundTripper) RoundTripFunc {
+	return func(req *Request) (resp *Response, err error) {
+		store := req.client.cacheStore
+		if store == nil || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+			return rt.RoundTrip(req)
+		}
+		key := cacheKey(req)
+		cached, hit := store.Get(key)
+		var conditionalHeaders []string
+		if hit {
+			if etag := cached.ETag(); etag != "" && req.getHeader("If-None-Match") == "" {
+				req.Headers.Set("If-None-Match", etag)
+				conditionalHeaders = append(conditionalHeaders, "If-None-Match")
+			}
+			if lastModified := cached.LastModified(); lastModified != "" && req.getHeader("If-Modified-Since") == "" {
+				req.Headers.Set("If-Modified-Since", lastModified)
+				conditionalHeaders = append(conditionalHeaders, "If-Modified-Since")
+			}
+			if len(conditionalHeaders) > 0 {
+				dumpInfo(req, "cache hit, revalidating with "+strings.Join(conditionalHeaders, " and "))
+			}
+		}
+		resp, err = rt.RoundTrip(req)
+		for _, name := range conditionalHeaders {
+			req.Headers.Del(name)
+		}
+		if err != nil || resp.Response == nil {
+			return
+		}
+		if resp.StatusCode == http.StatusNotModified && len(conditionalHeaders) > 0 {
+			dumpInfo(req, "304 Not Modified, serving cached response")
+			resp.Body.Close()
+			header := cached.Header.Clone()
+			for k, vs := range resp.Header { // update the stored headers with the 304 response.
+				header[k] = vs
+			}
+			resp.Response = &http.Response{
+				Status:        strconv.Itoa(cached.StatusCode) + " " + http.StatusText(cached.StatusCode),
+				StatusCode:    cached.StatusCode,
+				Proto:         resp.Proto,
+				ProtoMajor:    resp.ProtoMajor,
+				ProtoMinor:    resp.ProtoMinor,
+				Header:        header,
+				Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+				ContentLength: int64(len(cached.Body)),
+				Request:       resp.Response.Request,
+			}
+			resp.body = cached.Body
+			return
+		}
+		if resp.StatusCode != http.StatusOK || resp.body == nil ||
+			(resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "") {
+			return
+		}
+		if ttl, ok := cacheTTL(resp.Response); ok {
+			store.Set(key, &CachedResponse{
+				StatusCode: resp.StatusCode,
+				Header:     resp.Header.Clone(),
+				Body:       resp.body,
+			}, ttl)
+			dumpInfo(req, "response is stored in cache")
+		}
+		return
+	}
+}
@@ -0,0 +1,126 @@
+package req
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestSetCache(t *testing.T) {
+	var hits, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		switch r.URL.Path {
+		case "/etag":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				atomic.AddInt32(&notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+		case "/last-modified":
+			if r.Header.Get("If-Modified-Since") == "Mon, 02 Jan 2006 15:04:05 GMT" {
+				atomic.AddInt32(&notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		case "/no-store":
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		w.Write([]byte("hello " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	buf := new(bytes.Buffer)
+	c := C().SetBaseURL(server.URL).SetCache(NewInMemoryCache(10))
+	for _, path := range []string{"/etag", "/last-modified"} {
+		for i := 0; i < 2; i++ {
+			resp, err := c.R().EnableDumpTo(buf).Get(path)
+			assertSuccess(t, resp, err)
+			tests.AssertEqual(t, "hello "+path, resp.String())
+		}
+	}
+	tests.AssertEqual(t, int32(4), atomic.LoadInt32(&hits))
+	tests.AssertEqual(t, int32(2), atomic.LoadInt32(&notModified))
+	tests.AssertContains(t, buf.String(), "if-none-match", true)
+	tests.AssertContains(t, buf.String(), "serving cached response", true)
+
+	// the cache key contains the relevant headers
+	resp, err := c.R().SetHeader("Accept-Language", "fr").Get("/etag")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, int32(2), atomic.LoadInt32(&notModified))
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.R().Get("/no-store")
+		assertSuccess(t, resp, err)
+	}
+	tests.AssertEqual(t, int32(2), atomic.LoadInt32(&notModified))
+}
+
+func TestInMemoryCache(t *testing.T) {
+	cache := NewInMemoryCache(2)
+	cache.Set("a", &CachedResponse{Body: []byte("a")}, 0)
+	cache.Set("b", &CachedResponse{Body: []byte("b")}, 0)
+	_, ok := cache.Get("a")
+	tests.AssertEqual(t, true, ok)
+	cache.Set("c", &CachedResponse{Body: []byte("c")}, 0) // evict the least recently used "b"
+	_, ok = cache.Get("b")
+	tests.AssertEqual(t, false, ok)
+	_, ok = cache.Get("a")
+	tests.AssertEqual(t, true, ok)
+
+	cache.Set("d", &CachedResponse{Body: []byte("d")}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get("d")
+	tests.AssertEqual(t, false, ok)
+}
+
+func TestConditionalRequest(t *testing.T) {
+	lastModified := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` ||
+			r.Header.Get("If-Modified-Since") == lastModified.Format(http.TimeFormat) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v2"`)
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	c := C().SetBaseURL(server.URL)
+	resp, err := c.R().SetIfNoneMatch("v1").Get("/")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, true, resp.IsNotModified())
+	tests.AssertEqual(t, `"v1"`, resp.Request.Headers.Get("If-None-Match"))
+
+	// the quoted and weak etags are kept as they are.
+	resp, err = c.R().SetIfNoneMatch(`W/"v1"`).Get("/")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, false, resp.IsNotModified())
+	tests.AssertEqual(t, `W/"v1"`, resp.Request.Headers.Get("If-None-Match"))
+
+	resp, err = c.R().SetIfModifiedSince(lastModified.In(time.FixedZone("UTC+8", 8*3600))).Get("/")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, true, resp.IsNotModified())
+	tests.AssertEqual(t, "Mon, 02 Jan 2006 15:04:05 GMT", resp.Request.Headers.Get("If-Modified-Since"))
+
+	// the conditional header set by the user takes precedence over the cache.
+	c.SetCache(NewInMemoryCache(10))
+	resp, err = c.R().Get("/")
+	assertSuccess(t, resp, err)
+	resp, err = c.R().SetIfNoneMatch("v1").Get("/")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, true, resp.IsNotModified())
+	resp, err = c.R().Get("/")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, false, resp.IsNotModified())
+	tests.AssertEqual(t, "hello", resp.String())
+}
@@ -0,0 +1,26 @@
+package req
+
+import (
+	"errors"
+)
+
+// ErrCircuitOpen is returned when the CircuitBreaker does not allow the
+// request to be sent.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker is the interface of circuit breaker, which can be set by
+// Client.SetCircuitBreaker, users can implement it by wrapping a third-party
+// circuit breaker (e.g. gobreaker or hystrix-go).
+type CircuitBreaker interface {
+	// Allow reports whether a request is allowed to be sent, the request
+	// fails immediately with ErrCircuitOpen if it returns false.
+	Allow() bool
+	// Record records the result of each attempt of the request which is sent,
+	// success is false if the request fails to be sent or the HTTP status code
+	// >= 500. The errors which occur after the response is received (e.g. the
+	// unexpected status checked by Request.ExpectStatus or the error returned by
+	// the response middleware) are not failures. It is not called if the
+	// request fails before it is sent (e.g. the request body is invalid), or
+	// the response is replayed by Client.ReplayRequests.
+	Record(success bool)
+}
@@ -3,9 +3,13 @@ package req
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"github.com/imroc/req/v3/http2"
+	"github.com/prometheus/client_golang/prometheus"
 	utls "github.com/refraction-networking/utls"
+	"go.opentelemetry.io/otel/trace"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
@@ -24,6 +28,12 @@ func WrapRoundTripFunc(funcs ...RoundTripWrapperFunc) *Client {
 	return defaultClient.WrapRoundTripFunc(funcs...)
 }
 
+// Use is a global wrapper methods which delegated
+// to the default client's Client.Use.
+func Use(middlewares ...Middleware) *Client {
+	return defaultClient.Use(middlewares...)
+}
+
 // SetCommonError is a global wrapper methods which delegated
 // to the default client's Client.SetCommonErrorResult.
 //
@@ -44,6 +54,12 @@ func SetResultStateCheckFunc(fn func(resp *Response) ResultState) *Client {
 	return defaultClient.SetResultStateCheckFunc(fn)
 }
 
+// SetResponseStatusCheckFunc is a global wrapper methods which delegated
+// to the default client's Client.SetResponseStatusCheckFunc.
+func SetResponseStatusCheckFunc(fn func(resp *Response) error) *Client {
+	return defaultClient.SetResponseStatusCheckFunc(fn)
+}
+
 // SetCommonFormDataFromValues is a global wrapper methods which delegated
 // to the default client's Client.SetCommonFormDataFromValues.
 func SetCommonFormDataFromValues(data url.Values) *Client {
@@ -62,6 +78,12 @@ func SetBaseURL(u string) *Client {
 	return defaultClient.SetBaseURL(u)
 }
 
+// SetCommonPathPrefix is a global wrapper methods which delegated
+// to the default client's Client.SetCommonPathPrefix.
+func SetCommonPathPrefix(prefix string) *Client {
+	return defaultClient.SetCommonPathPrefix(prefix)
+}
+
 // SetOutputDirectory is a global wrapper methods which delegated
 // to the default client's Client.SetOutputDirectory.
 func SetOutputDirectory(dir string) *Client {
@@ -74,6 +96,18 @@ func SetCertFromFile(certFile, keyFile string) *Client {
 	return defaultClient.SetCertFromFile(certFile, keyFile)
 }
 
+// MutualTLS is a global wrapper methods which delegated
+// to the default client's Client.MutualTLS.
+func MutualTLS(certPEM, keyPEM []byte) (*Client, error) {
+	return defaultClient.MutualTLS(certPEM, keyPEM)
+}
+
+// MutualTLSFromFiles is a global wrapper methods which delegated
+// to the default client's Client.MutualTLSFromFiles.
+func MutualTLSFromFiles(certFile, keyFile string) (*Client, error) {
+	return defaultClient.MutualTLSFromFiles(certFile, keyFile)
+}
+
 // SetCerts is a global wrapper methods which delegated
 // to the default client's Client.SetCerts.
 func SetCerts(certs ...tls.Certificate) *Client {
@@ -104,6 +138,36 @@ func SetRedirectPolicy(policies ...RedirectPolicy) *Client {
 	return defaultClient.SetRedirectPolicy(policies...)
 }
 
+// SetCheckRedirect is a global wrapper methods which delegated
+// to the default client's Client.SetCheckRedirect.
+func SetCheckRedirect(fn func(req *http.Request, via []*http.Request) error) *Client {
+	return defaultClient.SetCheckRedirect(fn)
+}
+
+// EnableForwardAuthOnRedirect is a global wrapper methods which delegated
+// to the default client's Client.EnableForwardAuthOnRedirect.
+func EnableForwardAuthOnRedirect() *Client {
+	return defaultClient.EnableForwardAuthOnRedirect()
+}
+
+// DisableForwardAuthOnRedirect is a global wrapper methods which delegated
+// to the default client's Client.DisableForwardAuthOnRedirect.
+func DisableForwardAuthOnRedirect() *Client {
+	return defaultClient.DisableForwardAuthOnRedirect()
+}
+
+// DisableAutoRedirect is a global wrapper methods which delegated
+// to the default client's Client.DisableAutoRedirect.
+func DisableAutoRedirect() *Client {
+	return defaultClient.DisableAutoRedirect()
+}
+
+// EnableAutoRedirect is a global wrapper methods which delegated
+// to the default client's Client.EnableAutoRedirect.
+func EnableAutoRedirect() *Client {
+	return defaultClient.EnableAutoRedirect()
+}
+
 // DisableKeepAlives is a global wrapper methods which delegated
 // to the default client's Client.DisableKeepAlives.
 func DisableKeepAlives() *Client {
@@ -134,6 +198,24 @@ func SetTLSClientConfig(conf *tls.Config) *Client {
 	return defaultClient.SetTLSClientConfig(conf)
 }
 
+// SetRootCAs is a global wrapper methods which delegated
+// to the default client's Client.SetRootCAs.
+func SetRootCAs(pool *x509.CertPool) *Client {
+	return defaultClient.SetRootCAs(pool)
+}
+
+// SetTLSMinVersion is a global wrapper methods which delegated
+// to the default client's Client.SetTLSMinVersion.
+func SetTLSMinVersion(version uint16) *Client {
+	return defaultClient.SetTLSMinVersion(version)
+}
+
+// SetTLSMaxVersion is a global wrapper methods which delegated
+// to the default client's Client.SetTLSMaxVersion.
+func SetTLSMaxVersion(version uint16) *Client {
+	return defaultClient.SetTLSMaxVersion(version)
+}
+
 // EnableInsecureSkipVerify is a global wrapper methods which delegated
 // to the default client's Client.EnableInsecureSkipVerify.
 func EnableInsecureSkipVerify() *Client {
@@ -152,6 +234,12 @@ func SetCommonQueryParams(params map[string]string) *Client {
 	return defaultClient.SetCommonQueryParams(params)
 }
 
+// SetCommonQueryParamsFromValues is a global wrapper methods which delegated
+// to the default client's Client.SetCommonQueryParamsFromValues.
+func SetCommonQueryParamsFromValues(params url.Values) *Client {
+	return defaultClient.SetCommonQueryParamsFromValues(params)
+}
+
 // AddCommonQueryParam is a global wrapper methods which delegated
 // to the default client's Client.AddCommonQueryParam.
 func AddCommonQueryParam(key, value string) *Client {
@@ -224,6 +312,12 @@ func SetLogger(log Logger) *Client {
 	return defaultClient.SetLogger(log)
 }
 
+// SetSlogLogger is a global wrapper methods which delegated
+// to the default client's Client.SetSlogLogger.
+func SetSlogLogger(l *slog.Logger) *Client {
+	return defaultClient.SetSlogLogger(l)
+}
+
 // SetTimeout is a global wrapper methods which delegated
 // to the default client's Client.SetTimeout.
 func SetTimeout(d time.Duration) *Client {
@@ -386,18 +480,48 @@ func SetCommonBearerAuthToken(token string) *Client {
 	return defaultClient.SetCommonBearerAuthToken(token)
 }
 
+// SetCommonBearerAuthTokenFromEnv is a global wrapper methods which delegated
+// to the default client's Client.SetCommonBearerAuthTokenFromEnv.
+func SetCommonBearerAuthTokenFromEnv(tokenEnv string) *Client {
+	return defaultClient.SetCommonBearerAuthTokenFromEnv(tokenEnv)
+}
+
+// SetTraceIDHeader is a global wrapper methods which delegated
+// to the default client's Client.SetTraceIDHeader.
+func SetTraceIDHeader(name string) *Client {
+	return defaultClient.SetTraceIDHeader(name)
+}
+
 // SetCommonBasicAuth is a global wrapper methods which delegated
 // to the default client's Client.SetCommonBasicAuth.
 func SetCommonBasicAuth(username, password string) *Client {
 	return defaultClient.SetCommonBasicAuth(username, password)
 }
 
+// SetCommonAWSV4Auth is a global wrapper methods which delegated
+// to the default client's Client.SetCommonAWSV4Auth.
+func SetCommonAWSV4Auth(region, service, accessKeyID, secretKey string) *Client {
+	return defaultClient.SetCommonAWSV4Auth(region, service, accessKeyID, secretKey)
+}
+
+// SetCommonAWSV4AuthWithSession is a global wrapper methods which delegated
+// to the default client's Client.SetCommonAWSV4AuthWithSession.
+func SetCommonAWSV4AuthWithSession(region, service, accessKeyID, secretKey, sessionToken string) *Client {
+	return defaultClient.SetCommonAWSV4AuthWithSession(region, service, accessKeyID, secretKey, sessionToken)
+}
+
 // SetCommonDigestAuth is a global wrapper methods which delegated
 // to the default client's Client.SetCommonDigestAuth.
 func SetCommonDigestAuth(username, password string) *Client {
 	return defaultClient.SetCommonDigestAuth(username, password)
 }
 
+// SetCommonOAuth2ClientCredentials is a global wrapper methods which delegated
+// to the default client's Client.SetCommonOAuth2ClientCredentials.
+func SetCommonOAuth2ClientCredentials(tokenURL, clientID, clientSecret string, scopes ...string) *Client {
+	return defaultClient.SetCommonOAuth2ClientCredentials(tokenURL, clientID, clientSecret, scopes...)
+}
+
 // SetCommonHeaders is a global wrapper methods which delegated
 // to the default client's Client.SetCommonHeaders.
 func SetCommonHeaders(hdrs map[string]string) *Client {
@@ -410,6 +534,12 @@ func SetCommonHeader(key, value string) *Client {
 	return defaultClient.SetCommonHeader(key, value)
 }
 
+// RemoveCommonHeader is a global wrapper methods which delegated
+// to the default client's Client.RemoveCommonHeader.
+func RemoveCommonHeader(key string) *Client {
+	return defaultClient.RemoveCommonHeader(key)
+}
+
 // SetCommonHeaderOrder is a global wrapper methods which delegated
 // to the default client's Client.SetCommonHeaderOrder.
 func SetCommonHeaderOrder(keys ...string) *Client {
@@ -482,6 +612,12 @@ func ImpersonateChrome() *Client {
 	return defaultClient.ImpersonateChrome()
 }
 
+// SetCommonAccept is a global wrapper methods which delegated
+// to the default client's Client.SetCommonAccept.
+func SetCommonAccept(accept string) *Client {
+	return defaultClient.SetCommonAccept(accept)
+}
+
 // SetCommonContentType is a global wrapper methods which delegated
 // to the default client's Client.SetCommonContentType.
 func SetCommonContentType(ct string) *Client {
@@ -506,18 +642,150 @@ func SetProxy(proxy func(*http.Request) (*url.URL, error)) *Client {
 	return defaultClient.SetProxy(proxy)
 }
 
+// SetCircuitBreaker is a global wrapper methods which delegated
+// to the default client's Client.SetCircuitBreaker.
+func SetCircuitBreaker(cb CircuitBreaker) *Client {
+	return defaultClient.SetCircuitBreaker(cb)
+}
+
+// SetMaxInFlightRequests is a global wrapper methods which delegated
+// to the default client's Client.SetMaxInFlightRequests.
+func SetMaxInFlightRequests(n int) *Client {
+	return defaultClient.SetMaxInFlightRequests(n)
+}
+
+// SetRateLimit is a global wrapper methods which delegated
+// to the default client's Client.SetRateLimit.
+func SetRateLimit(rps float64, burst int) *Client {
+	return defaultClient.SetRateLimit(rps, burst)
+}
+
+// SetRateLimitPerHost is a global wrapper methods which delegated
+// to the default client's Client.SetRateLimitPerHost.
+func SetRateLimitPerHost(host string, rps float64, burst int) *Client {
+	return defaultClient.SetRateLimitPerHost(host, rps, burst)
+}
+
+// SetRequestSigner is a global wrapper methods which delegated
+// to the default client's Client.SetRequestSigner.
+func SetRequestSigner(keyID, secret string, signer SignerFunc) *Client {
+	return defaultClient.SetRequestSigner(keyID, secret, signer)
+}
+
+// EnableSingleFlight is a global wrapper methods which delegated
+// to the default client's Client.EnableSingleFlight.
+func EnableSingleFlight() *Client {
+	return defaultClient.EnableSingleFlight()
+}
+
+// DisableSingleFlight is a global wrapper methods which delegated
+// to the default client's Client.DisableSingleFlight.
+func DisableSingleFlight() *Client {
+	return defaultClient.DisableSingleFlight()
+}
+
+// EnablePrometheusMetrics is a global wrapper methods which delegated
+// to the default client's Client.EnablePrometheusMetrics.
+func EnablePrometheusMetrics(reg prometheus.Registerer, clientName string) *Client {
+	return defaultClient.EnablePrometheusMetrics(reg, clientName)
+}
+
+// EnableTracing is a global wrapper methods which delegated
+// to the default client's Client.EnableTracing.
+func EnableTracing(tp trace.TracerProvider) *Client {
+	return defaultClient.EnableTracing(tp)
+}
+
+// SetCache is a global wrapper methods which delegated
+// to the default client's Client.SetCache.
+func SetCache(store CacheStore) *Client {
+	return defaultClient.SetCache(store)
+}
+
+// RecordRequests is a global wrapper methods which delegated
+// to the default client's Client.RecordRequests.
+func RecordRequests(r Recorder) *Client {
+	return defaultClient.RecordRequests(r)
+}
+
+// ReplayRequests is a global wrapper methods which delegated
+// to the default client's Client.ReplayRequests.
+func ReplayRequests(r Recorder) *Client {
+	return defaultClient.ReplayRequests(r)
+}
+
+// EventHook is a global wrapper methods which delegated
+// to the default client's Client.EventHook.
+func EventHook(event Event, fn HookFunc) *Client {
+	return defaultClient.EventHook(event, fn)
+}
+
 // OnBeforeRequest is a global wrapper methods which delegated
 // to the default client's Client.OnBeforeRequest.
 func OnBeforeRequest(m RequestMiddleware) *Client {
 	return defaultClient.OnBeforeRequest(m)
 }
 
+// OnBeforeSend is a global wrapper methods which delegated
+// to the default client's Client.OnBeforeSend.
+func OnBeforeSend(m RawRequestMiddleware) *Client {
+	return defaultClient.OnBeforeSend(m)
+}
+
 // OnAfterResponse is a global wrapper methods which delegated
 // to the default client's Client.OnAfterResponse.
 func OnAfterResponse(m ResponseMiddleware) *Client {
 	return defaultClient.OnAfterResponse(m)
 }
 
+// SetNoProxy is a global wrapper methods which delegated
+// to the default client's Client.SetNoProxy.
+func SetNoProxy(hosts ...string) *Client {
+	return defaultClient.SetNoProxy(hosts...)
+}
+
+// SetMaxResponseBodySize is a global wrapper methods which delegated
+// to the default client's Client.SetMaxResponseBodySize.
+func SetMaxResponseBodySize(n int64) *Client {
+	return defaultClient.SetMaxResponseBodySize(n)
+}
+
+// SetTransportMetrics is a global wrapper methods which delegated
+// to the default client's Client.SetTransportMetrics.
+func SetTransportMetrics(m TransportMetrics) *Client {
+	return defaultClient.SetTransportMetrics(m)
+}
+
+// SetIdleConnTimeout is a global wrapper methods which delegated
+// to the default client's Client.SetIdleConnTimeout.
+func SetIdleConnTimeout(timeout time.Duration) *Client {
+	return defaultClient.SetIdleConnTimeout(timeout)
+}
+
+// SetMaxIdleConns is a global wrapper methods which delegated
+// to the default client's Client.SetMaxIdleConns.
+func SetMaxIdleConns(max int) *Client {
+	return defaultClient.SetMaxIdleConns(max)
+}
+
+// SetMaxIdleConnsPerHost is a global wrapper methods which delegated
+// to the default client's Client.SetMaxIdleConnsPerHost.
+func SetMaxIdleConnsPerHost(max int) *Client {
+	return defaultClient.SetMaxIdleConnsPerHost(max)
+}
+
+// SetMaxConnsPerHost is a global wrapper methods which delegated
+// to the default client's Client.SetMaxConnsPerHost.
+func SetMaxConnsPerHost(max int) *Client {
+	return defaultClient.SetMaxConnsPerHost(max)
+}
+
+// SetSOCKS5Proxy is a global wrapper methods which delegated
+// to the default client's Client.SetSOCKS5Proxy.
+func SetSOCKS5Proxy(addr, username, password string) (*Client, error) {
+	return defaultClient.SetSOCKS5Proxy(addr, username, password)
+}
+
 // SetProxyURL is a global wrapper methods which delegated
 // to the default client's Client.SetProxyURL.
 func SetProxyURL(proxyUrl string) *Client {
@@ -542,6 +810,18 @@ func SetCookieJar(jar http.CookieJar) *Client {
 	return defaultClient.SetCookieJar(jar)
 }
 
+// DisableCookieJar is a global wrapper methods which delegated
+// to the default client's Client.DisableCookieJar.
+func DisableCookieJar() *Client {
+	return defaultClient.DisableCookieJar()
+}
+
+// EnableCookieJar is a global wrapper methods which delegated
+// to the default client's Client.EnableCookieJar.
+func EnableCookieJar() *Client {
+	return defaultClient.EnableCookieJar()
+}
+
 // GetCookies is a global wrapper methods which delegated
 // to the default client's Client.GetCookies.
 func GetCookies(url string) ([]*http.Cookie, error) {
@@ -578,12 +858,36 @@ func SetXmlUnmarshal(fn func(data []byte, v interface{}) error) *Client {
 	return defaultClient.SetXmlUnmarshal(fn)
 }
 
+// SetMsgpackMarshal is a global wrapper methods which delegated
+// to the default client's Client.SetMsgpackMarshal.
+func SetMsgpackMarshal(fn func(v interface{}) ([]byte, error)) *Client {
+	return defaultClient.SetMsgpackMarshal(fn)
+}
+
+// SetMsgpackUnmarshal is a global wrapper methods which delegated
+// to the default client's Client.SetMsgpackUnmarshal.
+func SetMsgpackUnmarshal(fn func(data []byte, v interface{}) error) *Client {
+	return defaultClient.SetMsgpackUnmarshal(fn)
+}
+
 // SetDialTLS is a global wrapper methods which delegated
 // to the default client's Client.SetDialTLS.
 func SetDialTLS(fn func(ctx context.Context, network, addr string) (net.Conn, error)) *Client {
 	return defaultClient.SetDialTLS(fn)
 }
 
+// SetHostResolver is a global wrapper methods which delegated
+// to the default client's Client.SetHostResolver.
+func SetHostResolver(fn func(host string) (string, error)) *Client {
+	return defaultClient.SetHostResolver(fn)
+}
+
+// SetHostResolverTTL is a global wrapper methods which delegated
+// to the default client's Client.SetHostResolverTTL.
+func SetHostResolverTTL(ttl time.Duration) *Client {
+	return defaultClient.SetHostResolverTTL(ttl)
+}
+
 // SetDial is a global wrapper methods which delegated
 // to the default client's Client.SetDial.
 func SetDial(fn func(ctx context.Context, network, addr string) (net.Conn, error)) *Client {
@@ -596,12 +900,24 @@ func SetTLSHandshakeTimeout(timeout time.Duration) *Client {
 	return defaultClient.SetTLSHandshakeTimeout(timeout)
 }
 
+// SetExpectContinueTimeout is a global wrapper methods which delegated
+// to the default client's Client.SetExpectContinueTimeout.
+func SetExpectContinueTimeout(timeout time.Duration) *Client {
+	return defaultClient.SetExpectContinueTimeout(timeout)
+}
+
 // EnableForceHTTP1 is a global wrapper methods which delegated
 // to the default client's Client.EnableForceHTTP1.
 func EnableForceHTTP1() *Client {
 	return defaultClient.EnableForceHTTP1()
 }
 
+// DisableHTTP2 is a global wrapper methods which delegated
+// to the default client's Client.DisableHTTP2.
+func DisableHTTP2() *Client {
+	return defaultClient.DisableHTTP2()
+}
+
 // EnableForceHTTP2 is a global wrapper methods which delegated
 // to the default client's Client.EnableForceHTTP2.
 func EnableForceHTTP2() *Client {
@@ -614,6 +930,12 @@ func EnableForceHTTP3() *Client {
 	return defaultClient.EnableForceHTTP3()
 }
 
+// HTTP3 is a global wrapper methods which delegated
+// to the default client's Client.HTTP3.
+func HTTP3(enabled bool) *Client {
+	return defaultClient.HTTP3(enabled)
+}
+
 // EnableHTTP3 is a global wrapper methods which delegated
 // to the default client's Client.EnableHTTP3.
 func EnableHTTP3() *Client {
@@ -674,6 +996,18 @@ func SetCommonRetryBackoffInterval(min, max time.Duration) *Client {
 	return defaultClient.SetCommonRetryBackoffInterval(min, max)
 }
 
+// SetCommonRetryExponentialInterval is a global wrapper methods which delegated
+// to the default client's Client.SetCommonRetryExponentialInterval.
+func SetCommonRetryExponentialInterval(initial, max time.Duration, multiplier float64) *Client {
+	return defaultClient.SetCommonRetryExponentialInterval(initial, max, multiplier)
+}
+
+// SetCommonRetryMaxWait is a global wrapper methods which delegated
+// to the default client's Client.SetCommonRetryMaxWait.
+func SetCommonRetryMaxWait(max time.Duration) *Client {
+	return defaultClient.SetCommonRetryMaxWait(max)
+}
+
 // SetCommonRetryHook is a global wrapper methods which delegated
 // to the default client's Client.SetCommonRetryHook.
 func SetCommonRetryHook(hook RetryHookFunc) *Client {
@@ -698,12 +1032,24 @@ func AddCommonRetryCondition(condition RetryConditionFunc) *Client {
 	return defaultClient.AddCommonRetryCondition(condition)
 }
 
+// SetTransport is a global wrapper methods which delegated
+// to the default client's Client.SetTransport.
+func SetTransport(t http.RoundTripper) *Client {
+	return defaultClient.SetTransport(t)
+}
+
 // SetResponseBodyTransformer is a global wrapper methods which delegated
 // to the default client's Client.SetResponseBodyTransformer.
 func SetResponseBodyTransformer(fn func(rawBody []byte, req *Request, resp *Response) (transformedBody []byte, err error)) *Client {
 	return defaultClient.SetResponseBodyTransformer(fn)
 }
 
+// AddCommonRetryOnStatus is a global wrapper methods which delegated
+// to the default client's Client.AddCommonRetryOnStatus.
+func AddCommonRetryOnStatus(codes ...int) *Client {
+	return defaultClient.AddCommonRetryOnStatus(codes...)
+}
+
 // SetUnixSocket is a global wrapper methods which delegated
 // to the default client's Client.SetUnixSocket.
 func SetUnixSocket(file string) *Client {
@@ -770,6 +1116,18 @@ func SetTLSFingerprintSafari() *Client {
 	return defaultClient.SetTLSFingerprintSafari()
 }
 
+// Parallel is a global wrapper methods which delegated
+// to the default client's Client.Parallel.
+func Parallel(ctx context.Context, n int, reqs ...*Request) []*ParallelResult {
+	return defaultClient.Parallel(ctx, n, reqs...)
+}
+
+// MultiGet is a global wrapper methods which delegated
+// to the default client's Client.MultiGet.
+func MultiGet(ctx context.Context, urls ...string) ([]*Response, []error) {
+	return defaultClient.MultiGet(ctx, urls...)
+}
+
 // GetClient is a global wrapper methods which delegated
 // to the default client's Client.GetClient.
 func GetClient() *http.Client {
@@ -0,0 +1,55 @@
+package req
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Connect sends the CONNECT request to the HTTP proxy specified by the request
+// url (e.g. "http://proxy.example.com:8080") to establish a tunnel to targetHost
+// ("host:port"). If the proxy responds with 2xx status code, the tunnel is
+// returned by Response.Tunnel, through which the raw bytes are sent to and
+// received from the target. Only HTTP/1.1 proxy is supported.
+//
+// The CONNECT handshake goes through the middlewares and the dump like other
+// requests, while the bytes of the tunnel are not dumped.
+//
+// For Example:
+//
+//	resp, err := client.R().SetURL("http://proxy.example.com:8080").Connect("example.com:22")
+//	if err != nil {
+//		return err
+//	}
+//	tunnel := resp.Tunnel()
+//	defer tunnel.Close()
+func (r *Request) Connect(targetHost string) (*Response, error) {
+	if targetHost == "" {
+		return nil, errors.New("connect: empty target host")
+	}
+	sc := &switchedConn{}
+	r.SetHeader("Host", targetHost).DisableAutoReadResponse()
+	r.SetContext(context.WithValue(r.Context(), switchedConnKey, sc))
+	resp, err := r.Send(http.MethodConnect, "")
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return resp, fmt.Errorf("connect: bad response status: %s", resp.Status)
+	}
+	if sc.rwc == nil {
+		resp.Body.Close()
+		return resp, errors.New("connect: the response body is not writable")
+	}
+	resp.tunnel = sc.rwc
+	return resp, nil
+}
+
+// Tunnel returns the tunnel established by Request.Connect, which is nil if
+// the tunnel is not established. The caller should close it after use.
+func (r *Response) Tunnel() io.ReadWriteCloser {
+	return r.tunnel
+}
@@ -0,0 +1,49 @@
+package req
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestConnect(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect || r.Host != "example.com:22" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		io.Copy(conn, brw) // echo
+	}))
+	defer proxy.Close()
+
+	buf := new(bytes.Buffer)
+	resp, err := C().R().EnableDumpTo(buf).SetURL(proxy.URL).Connect("example.com:22")
+	tests.AssertNoError(t, err)
+	tunnel := resp.Tunnel()
+	tests.AssertNotNil(t, tunnel)
+	_, err = tunnel.Write([]byte("ping"))
+	tests.AssertNoError(t, err)
+	b := make([]byte, 4)
+	_, err = io.ReadFull(tunnel, b)
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, "ping", string(b))
+	tests.AssertNoError(t, tunnel.Close())
+
+	tests.AssertContains(t, buf.String(), "connect example.com:22 http/1.1", true)
+	tests.AssertContains(t, buf.String(), "200 connection established", true)
+	tests.AssertContains(t, buf.String(), "ping", false)
+
+	resp, err = C().R().SetURL(proxy.URL).Connect("example.com:80")
+	tests.AssertErrorContains(t, err, "403 Forbidden")
+	tests.AssertIsNil(t, resp.Tunnel())
+}
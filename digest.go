@@ -1,6 +1,7 @@
 package req
 
 import (
+	"bytes"
 	"crypto/md5"
 	"crypto/rand"
 	"crypto/sha256"
@@ -42,6 +43,13 @@ func handleDigestAuthFunc(username, password string) ResponseMiddleware {
 		if err != nil {
 			return err
 		}
+		// drain and close the body of the challenge response, so that the
+		// connection can be reused by the authorized request if keep-alive.
+		autoRead := resp.body != nil
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		resp.body = nil
+
 		r := resp.Request
 		req := *r.RawRequest
 		if req.Body != nil {
@@ -63,7 +71,16 @@ func handleDigestAuthFunc(username, password string) ResponseMiddleware {
 		}
 		req.Header.Set(header.Authorization, auth)
 		resp.Response, err = client.GetTransport().RoundTrip(&req)
-		return err
+		if err != nil {
+			return err
+		}
+		if autoRead {
+			if _, err = resp.ToBytes(); err != nil {
+				return err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(resp.body))
+		}
+		return nil
 	}
 }
 
@@ -120,7 +137,7 @@ func parseChallenge(input string) (*challenge, error) {
 		return nil, errDigestBadChallenge
 	}
 	s = strings.Trim(s[7:], ws)
-	sl := strings.Split(s, ",")
+	sl := splitChallengeParams(s)
 	c := &challenge{}
 	var r []string
 	for i := range sl {
@@ -140,7 +157,7 @@ func parseChallenge(input string) (*challenge, error) {
 		case "stale":
 			c.stale = r[1]
 		case "algorithm":
-			c.algorithm = r[1]
+			c.algorithm = strings.Trim(r[1], qs)
 		case "qop":
 			c.qop = strings.Trim(r[1], qs)
 		case "charset":
@@ -156,6 +173,31 @@ func parseChallenge(input string) (*challenge, error) {
 	return c, nil
 }
 
+// splitChallengeParams splits the comma separated auth-params of the
+// challenge, commas inside the quoted-string are not treated as separators,
+// e.g. `realm="test", qop="auth,auth-int"`.
+func splitChallengeParams(s string) []string {
+	var params []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			if quoted {
+				i++
+			}
+		case ',':
+			if !quoted {
+				params = append(params, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(params, s[start:])
+}
+
 type credentials struct {
 	username   string
 	userhash   string
@@ -215,19 +257,13 @@ func (c *credentials) validateQop() error {
 	if c.messageQop == "" {
 		return nil
 	}
-	possibleQops := strings.Split(c.messageQop, ", ")
-	var authSupport bool
-	for _, qop := range possibleQops {
-		if qop == "auth" {
-			authSupport = true
-			break
+	for _, qop := range strings.Split(c.messageQop, ",") {
+		if strings.TrimSpace(qop) == "auth" {
+			c.messageQop = "auth" // the server may offer multiple qop, choose the supported one.
+			return nil
 		}
 	}
-	if !authSupport {
-		return errDigestQopNotSupported
-	}
-
-	return nil
+	return errDigestQopNotSupported
 }
 
 func (c *credentials) h(data string) string {
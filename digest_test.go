@@ -0,0 +1,81 @@
+package req
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func md5Hex(s string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(s)))
+}
+
+func createDigestServer(username, password string) (*httptest.Server, *int32) {
+	var conns int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Digest ") {
+			w.Header().Set("WWW-Authenticate", `Digest realm="test", qop="auth,auth-int", nonce="abc", opaque="xyz", algorithm=MD5`)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("unauthorized"))
+			return
+		}
+		params := map[string]string{}
+		for _, param := range splitChallengeParams(auth[len("Digest "):]) {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			params[k] = strings.Trim(v, `"`)
+		}
+		ha1 := md5Hex(username + ":test:" + password)
+		ha2 := md5Hex(r.Method + ":" + r.URL.RequestURI())
+		expected := md5Hex(strings.Join([]string{ha1, "abc", params["nc"], params["cnonce"], "auth", ha2}, ":"))
+		if params["response"] != expected || params["qop"] != "auth" || params["nc"] != "00000001" ||
+			params["cnonce"] == "" || params["opaque"] != "xyz" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("authorized"))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Start()
+	return server, &conns
+}
+
+func TestSetDigestAuth(t *testing.T) {
+	server, conns := createDigestServer("roc", "123456")
+	defer server.Close()
+
+	resp, err := C().R().SetDigestAuth("roc", "123456").Get(server.URL + "/protected?a=b")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "authorized", resp.String())
+	tests.AssertEqual(t, int32(1), atomic.LoadInt32(conns)) // the connection is reused
+
+	resp, err = C().SetCommonDigestAuth("roc", "123456").R().SetBody("test").Post(server.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "authorized", resp.String())
+
+	resp, err = C().R().SetDigestAuth("roc", "wrong").Get(server.URL)
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestParseChallenge(t *testing.T) {
+	c, err := parseChallenge(`Digest realm="a, b", qop="auth,auth-int", nonce="n", algorithm="SHA-256"`)
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, "a, b", c.realm)
+	tests.AssertEqual(t, "auth,auth-int", c.qop)
+	tests.AssertEqual(t, "SHA-256", c.algorithm)
+
+	_, err = parseChallenge(`Basic realm="a"`)
+	tests.AssertEqual(t, errDigestBadChallenge, err)
+}
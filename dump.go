@@ -1,9 +1,30 @@
 package req
 
 import (
-	"github.com/imroc/req/v3/internal/dump"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"strings"
+	"time"
+
+	"github.com/imroc/req/v3/internal/dump"
+)
+
+// DumpFormat is the format of the dump output.
+type DumpFormat int
+
+const (
+	// DumpFormatText dumps the request and response in the HTTP wire format,
+	// which is the default format.
+	DumpFormatText DumpFormat = iota
+	// DumpFormatJSON dumps each request and response pair as a JSON object in
+	// a single line, with the "method", "url", "requestHeaders", "requestBody",
+	// "statusCode", "responseHeaders", "responseBody", "latencyMs" and "error"
+	// fields. The body is only dumped if it is read in memory, e.g. the
+	// response body which is not auto-read is omitted.
+	DumpFormatJSON
 )
 
 // DumpOptions controls the dump behavior.
@@ -20,14 +41,35 @@ type DumpOptions struct {
 	ResponseHeader       bool
 	ResponseBody         bool
 	Async                bool
+	// MaxBodySize caps how many bytes of the request and response body
+	// are dumped, the rest is omitted with a "[truncated: N bytes remaining]"
+	// suffix, zero means no limit. It does not affect the body actually read
+	// or written.
+	MaxBodySize int64
+	// SensitiveHeaders is the headers whose values are replaced with
+	// "[REDACTED]" in the dump output, the actual headers are not affected.
+	// If nil, DefaultSensitiveHeaders is used, set it to an empty slice to
+	// dump all headers as is.
+	SensitiveHeaders []string
+	// Format is the format of the dump output, DumpFormatText by default.
+	Format DumpFormat
 }
 
+// DefaultSensitiveHeaders is the default DumpOptions.SensitiveHeaders.
+var DefaultSensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
 // Clone return a copy of DumpOptions
 func (do *DumpOptions) Clone() *DumpOptions {
 	if do == nil {
 		return nil
 	}
 	d := *do
+	if do.SensitiveHeaders != nil {
+		d.SensitiveHeaders = cloneSlice(do.SensitiveHeaders)
+		if d.SensitiveHeaders == nil {
+			d.SensitiveHeaders = []string{}
+		}
+	}
 	return &d
 }
 
@@ -83,25 +125,36 @@ func (o dumpOptions) ResponseBodyOutput() io.Writer {
 }
 
 func (o dumpOptions) RequestHeader() bool {
-	return o.DumpOptions.RequestHeader
+	return o.DumpOptions.RequestHeader && o.Format == DumpFormatText
 }
 
 func (o dumpOptions) RequestBody() bool {
-	return o.DumpOptions.RequestBody
+	return o.DumpOptions.RequestBody && o.Format == DumpFormatText
 }
 
 func (o dumpOptions) ResponseHeader() bool {
-	return o.DumpOptions.ResponseHeader
+	return o.DumpOptions.ResponseHeader && o.Format == DumpFormatText
 }
 
 func (o dumpOptions) ResponseBody() bool {
-	return o.DumpOptions.ResponseBody
+	return o.DumpOptions.ResponseBody && o.Format == DumpFormatText
 }
 
 func (o dumpOptions) Async() bool {
 	return o.DumpOptions.Async
 }
 
+func (o dumpOptions) MaxBodySize() int64 {
+	return o.DumpOptions.MaxBodySize
+}
+
+func (o dumpOptions) SensitiveHeaders() []string {
+	if o.DumpOptions.SensitiveHeaders == nil {
+		return DefaultSensitiveHeaders
+	}
+	return o.DumpOptions.SensitiveHeaders
+}
+
 func (o dumpOptions) Clone() dump.Options {
 	return dumpOptions{o.DumpOptions.Clone()}
 }
@@ -125,3 +178,94 @@ func newDumper(opt *DumpOptions) *dump.Dumper {
 	}
 	return dump.NewDumper(dumpOptions{opt})
 }
+
+// dumpInfo writes the informational line which starts with "* " to the
+// client-level and request-level dumpers, e.g. retry attempt and cache hit.
+func dumpInfo(r *Request, info string) {
+	for _, d := range dump.GetDumpers(r.Context(), r.client.Dump) {
+		if _, ok := jsonDumpOptions(d); !ok {
+			d.DumpDefault([]byte("* " + info + "\r\n"))
+		}
+	}
+}
+
+// jsonDumpOptions returns the DumpOptions of the dumper if its format is
+// DumpFormatJSON, which is not dumped by the transport.
+func jsonDumpOptions(d *dump.Dumper) (*DumpOptions, bool) {
+	o, ok := d.Options.(dumpOptions)
+	if !ok || o.Format != DumpFormatJSON {
+		return nil, false
+	}
+	return o.DumpOptions, true
+}
+
+type dumpRecord struct {
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestHeaders  http.Header `json:"requestHeaders,omitempty"`
+	RequestBody     string      `json:"requestBody,omitempty"`
+	StatusCode      int         `json:"statusCode,omitempty"`
+	ResponseHeaders http.Header `json:"responseHeaders,omitempty"`
+	ResponseBody    string      `json:"responseBody,omitempty"`
+	LatencyMs       int64       `json:"latencyMs"`
+	Error           string      `json:"error,omitempty"`
+}
+
+// dumpJSON dumps the request and response pair to the dumpers with the
+// DumpFormatJSON format.
+func dumpJSON(r *Request, resp *Response) {
+	for _, d := range dump.GetDumpers(r.Context(), r.client.Dump) {
+		o, ok := jsonDumpOptions(d)
+		if !ok {
+			continue
+		}
+		record := dumpRecord{
+			Method:    r.RawRequest.Method,
+			URL:       r.RawRequest.URL.String(),
+			LatencyMs: time.Since(r.StartTime).Milliseconds(),
+		}
+		if o.RequestHeader {
+			record.RequestHeaders = o.redactHeader(r.RawRequest.Header)
+		}
+		if o.RequestBody {
+			record.RequestBody = o.truncateBody(r.Body)
+		}
+		if resp.Response != nil {
+			record.StatusCode = resp.StatusCode
+			if o.ResponseHeader {
+				record.ResponseHeaders = o.redactHeader(resp.Header)
+			}
+			if o.ResponseBody {
+				record.ResponseBody = o.truncateBody(resp.body)
+			}
+		}
+		if resp.Err != nil {
+			record.Error = resp.Err.Error()
+		}
+		b, err := json.Marshal(&record)
+		if err != nil {
+			continue
+		}
+		d.DumpDefault(append(b, '\n'))
+	}
+}
+
+func (do *DumpOptions) redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	sensitiveHeaders := dumpOptions{do}.SensitiveHeaders()
+	for key := range h {
+		for _, sensitive := range sensitiveHeaders {
+			if strings.EqualFold(key, sensitive) {
+				h[key] = []string{"[REDACTED]"}
+			}
+		}
+	}
+	return h
+}
+
+func (do *DumpOptions) truncateBody(body []byte) string {
+	if do.MaxBodySize > 0 && int64(len(body)) > do.MaxBodySize {
+		return fmt.Sprintf("%s\r\n[truncated: %d bytes remaining]", body[:do.MaxBodySize], int64(len(body))-do.MaxBodySize)
+	}
+	return string(body)
+}
@@ -0,0 +1,73 @@
+package req
+
+import (
+	"os"
+	"strings"
+)
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLErrorLocation is the location in the GraphQL document which
+// is associated with the GraphQLError.
+type GraphQLErrorLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLError is an error in the "errors" array of the GraphQL response.
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Locations  []GraphQLErrorLocation `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Error implements the error interface.
+func (e GraphQLError) Error() string {
+	return "graphql: " + e.Message
+}
+
+// SetGraphQL set the GraphQL query (or mutation) and variables as the JSON
+// request body, e.g. {"query": "...", "variables": {...}}, the request
+// should be sent with POST method.
+//
+// For Example:
+//
+//	client.R().SetGraphQL(`query ($id: ID!) { user(id: $id) { name } }`, map[string]interface{}{
+//		"id": "1",
+//	}).Post("https://api.example.com/graphql")
+func (r *Request) SetGraphQL(query string, variables map[string]interface{}) *Request {
+	return r.SetBodyJsonMarshal(&graphQLRequest{Query: query, Variables: variables})
+}
+
+// SetGraphQLFromFile is similar to SetGraphQL, but reads the query from
+// the file, e.g. "user.graphql".
+func (r *Request) SetGraphQLFromFile(file string, variables map[string]interface{}) *Request {
+	query, err := os.ReadFile(file)
+	if err != nil {
+		r.client.log.Errorf("failed to read graphql file %s: %v", file, err)
+		r.appendError(err)
+		return r
+	}
+	return r.SetGraphQL(strings.TrimSpace(string(query)), variables)
+}
+
+// GraphQLErrors returns the errors in the "errors" array of the GraphQL
+// response body, nil if there is no error or the body is not a GraphQL
+// response.
+func (r *Response) GraphQLErrors() []GraphQLError {
+	body, err := r.ToBytes()
+	if err != nil || len(body) == 0 {
+		return nil
+	}
+	var resp struct {
+		Errors []GraphQLError `json:"errors"`
+	}
+	if err = r.Request.client.jsonUnmarshal(body, &resp); err != nil {
+		return nil
+	}
+	return resp.Errors
+}
@@ -0,0 +1,58 @@
+package req
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imroc/req/v3/internal/header"
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestSetGraphQL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if r.Header.Get(header.ContentType) != header.JsonContentType || json.NewDecoder(r.Body).Decode(&req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.Variables["id"] != "1" {
+			w.Write([]byte(`{"data":null,"errors":[{"message":"user not found","locations":[{"line":1,"column":20}],"path":["user"]}]}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"user":{"name":"` + req.Query + `"}}}`))
+	}))
+	defer server.Close()
+
+	var result struct {
+		Data struct {
+			User struct {
+				Name string `json:"name"`
+			} `json:"user"`
+		} `json:"data"`
+	}
+	c := C()
+	resp, err := c.R().
+		SetGraphQL("query", map[string]interface{}{"id": "1"}).
+		SetSuccessResult(&result).
+		Post(server.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "query", result.Data.User.Name)
+	tests.AssertEqual(t, 0, len(resp.GraphQLErrors()))
+
+	file := filepath.Join(t.TempDir(), "user.graphql")
+	tests.AssertNoError(t, os.WriteFile(file, []byte("query user\n"), 0644))
+	resp, err = c.R().SetGraphQLFromFile(file, map[string]interface{}{"id": "2"}).Post(server.URL)
+	assertSuccess(t, resp, err)
+	errs := resp.GraphQLErrors()
+	tests.AssertEqual(t, 1, len(errs))
+	tests.AssertEqual(t, "user not found", errs[0].Message)
+	tests.AssertEqual(t, GraphQLErrorLocation{Line: 1, Column: 20}, errs[0].Locations[0])
+	tests.AssertEqual(t, "graphql: user not found", errs[0].Error())
+
+	_, err = c.R().SetGraphQLFromFile(filepath.Join(t.TempDir(), "missing.graphql"), nil).Post(server.URL)
+	tests.AssertNotNil(t, err)
+}
@@ -0,0 +1,19 @@
+package req
+
+import (
+	"github.com/PuerkitoBio/goquery"
+)
+
+// HTML parses the response body as HTML and returns the goquery document. The
+// body is decoded to UTF-8 before parsing if the charset is decoded automatically
+// (see Client.EnableAutoDecode). If the body is not auto-read (e.g.
+// DisableAutoReadResponse is called), it is streamed to the parser and can only
+// be consumed once, ErrBodyAlreadyRead is returned if it has been consumed.
+func (r *Response) HTML() (*goquery.Document, error) {
+	body, err := r.saveBodyReader()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return goquery.NewDocumentFromReader(body)
+}
@@ -0,0 +1,25 @@
+package req
+
+import (
+	"testing"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestResponseHTML(t *testing.T) {
+	c := tc().SetAutoDecodeAllContentType()
+	resp, err := c.R().Get("/gbk-no-charset")
+	assertSuccess(t, resp, err)
+	doc, err := resp.HTML()
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, "我是roc", doc.Find("title").Text())
+
+	// the body which is not auto-read can only be parsed once.
+	resp, err = c.R().DisableAutoReadResponse().Get("/gbk-no-charset")
+	assertSuccess(t, resp, err)
+	doc, err = resp.HTML()
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, "我是roc", doc.Find("title").Text())
+	_, err = resp.HTML()
+	tests.AssertEqual(t, ErrBodyAlreadyRead, err)
+}
@@ -40,6 +40,12 @@ func isProtocolSwitch(r *http.Response) bool {
 	return isProtocolSwitchResponse(r.StatusCode, r.Header)
 }
 
+// isTunnelEstablished reports whether the response is the successful
+// response of the CONNECT request, after which the connection is a tunnel.
+func isTunnelEstablished(req *http.Request, resp *http.Response) bool {
+	return req.Method == http.MethodConnect && resp.StatusCode >= 200 && resp.StatusCode <= 299
+}
+
 // isProtocolSwitchResponse reports whether the response code and
 // response header indicate a successful protocol upgrade response.
 func isProtocolSwitchResponse(code int, h http.Header) bool {
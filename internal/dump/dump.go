@@ -1,9 +1,15 @@
 package dump
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
+
+	"github.com/imroc/req/v3/internal/util"
 )
 
 // Options controls the dump behavior.
@@ -18,42 +24,60 @@ type Options interface {
 	ResponseHeader() bool
 	ResponseBody() bool
 	Async() bool
+	MaxBodySize() int64
+	SensitiveHeaders() []string
 	Clone() Options
 }
 
-func (d *Dumper) WrapResponseBodyReadCloser(rc io.ReadCloser) io.ReadCloser {
-	return &dumpReponseBodyReadCloser{rc, d}
+func (d *Dumper) WrapResponseBodyReadCloser(rc io.ReadCloser, contentType string) io.ReadCloser {
+	return &dumpReponseBodyReadCloser{rc, d, d.NewResponseBodyDumper(contentType)}
 }
 
 type dumpReponseBodyReadCloser struct {
 	io.ReadCloser
 	dump *Dumper
+	body *BodyDumper
 }
 
 func (r *dumpReponseBodyReadCloser) Read(p []byte) (n int, err error) {
 	n, err = r.ReadCloser.Read(p)
-	r.dump.DumpResponseBody(p[:n])
+	r.body.Dump(p[:n])
 	if err == io.EOF {
+		r.body.Finish()
 		r.dump.DumpDefault([]byte("\r\n"))
 	}
 	return
 }
 
-func (d *Dumper) WrapRequestBodyWriteCloser(rc io.WriteCloser) io.WriteCloser {
-	return &dumpRequestBodyWriteCloser{rc, d}
+// WrapChunkedWriteCloser wraps the chunked request body WriteCloser which
+// dumps the written body with the chunked encoding markers as on the wire.
+func (b *BodyDumper) WrapChunkedWriteCloser(rc io.WriteCloser) io.WriteCloser {
+	return &dumpChunkedRequestBodyWriteCloser{rc, b}
 }
 
-type dumpRequestBodyWriteCloser struct {
+type dumpChunkedRequestBodyWriteCloser struct {
 	io.WriteCloser
-	dump *Dumper
+	body *BodyDumper
 }
 
-func (w *dumpRequestBodyWriteCloser) Write(p []byte) (n int, err error) {
+func (w *dumpChunkedRequestBodyWriteCloser) Write(p []byte) (n int, err error) {
 	n, err = w.WriteCloser.Write(p)
-	w.dump.DumpRequestBody(p[:n])
+	if n > 0 {
+		w.body.Dump([]byte(fmt.Sprintf("%x\r\n", n)))
+		w.body.Dump(p[:n])
+		w.body.Dump([]byte("\r\n"))
+	}
 	return
 }
 
+func (w *dumpChunkedRequestBodyWriteCloser) Close() error {
+	err := w.WriteCloser.Close()
+	if err == nil {
+		w.body.Dump([]byte("0\r\n"))
+	}
+	return err
+}
+
 type dumpRequestHeaderWriter struct {
 	w    io.Writer
 	dump *Dumper
@@ -74,20 +98,95 @@ func (d *Dumper) WrapRequestHeaderWriter(w io.Writer) io.Writer {
 
 type dumpRequestBodyWriter struct {
 	w    io.Writer
-	dump *Dumper
+	body *BodyDumper
 }
 
 func (w *dumpRequestBodyWriter) Write(p []byte) (n int, err error) {
 	n, err = w.w.Write(p)
-	w.dump.DumpRequestBody(p[:n])
+	w.body.Dump(p[:n])
 	return
 }
 
-func (d *Dumper) WrapRequestBodyWriter(w io.Writer) io.Writer {
+// WrapWriter wraps the request body Writer which dumps the written body.
+func (b *BodyDumper) WrapWriter(w io.Writer) io.Writer {
 	return &dumpRequestBodyWriter{
 		w:    w,
-		dump: d,
+		body: b,
+	}
+}
+
+// BodyDumper dumps a single request or response body, the dumped content
+// is truncated if it exceeds Options.MaxBodySize. The binary body (e.g.
+// msgpack and protobuf) is dumped in hex.
+type BodyDumper struct {
+	dump      *Dumper
+	output    io.Writer
+	dumped    int64
+	truncated int64
+	hex       io.WriteCloser
+}
+
+func newBodyDumper(d *Dumper, output io.Writer, contentType string) *BodyDumper {
+	b := &BodyDumper{dump: d, output: output}
+	if util.IsMsgpackType(contentType) || util.IsProtobufType(contentType) {
+		b.hex = hex.Dumper(bodyDumperOutput{b})
+	}
+	return b
+}
+
+type bodyDumperOutput struct {
+	b *BodyDumper
+}
+
+func (w bodyDumperOutput) Write(p []byte) (int, error) {
+	w.b.dump.DumpTo(p, w.b.output)
+	return len(p), nil
+}
+
+// NewRequestBodyDumper creates a BodyDumper for the request body with the
+// contentType.
+func (d *Dumper) NewRequestBodyDumper(contentType string) *BodyDumper {
+	return newBodyDumper(d, d.RequestBodyOutput(), contentType)
+}
+
+// NewResponseBodyDumper creates a BodyDumper for the response body with the
+// contentType.
+func (d *Dumper) NewResponseBodyDumper(contentType string) *BodyDumper {
+	return newBodyDumper(d, d.ResponseBodyOutput(), contentType)
+}
+
+// Dump dumps the body content, the content beyond the max body size is
+// counted but not dumped.
+func (b *BodyDumper) Dump(p []byte) {
+	if max := b.dump.MaxBodySize(); max > 0 {
+		left := max - b.dumped
+		if left < 0 {
+			left = 0
+		}
+		if int64(len(p)) > left {
+			b.truncated += int64(len(p)) - left
+			p = p[:left]
+		}
+	}
+	b.dumped += int64(len(p))
+	if b.hex != nil {
+		b.hex.Write(p)
+		return
+	}
+	b.dump.DumpTo(p, b.output)
+}
+
+// Finish dumps the truncation suffix if the body has been truncated, it
+// should be called after the whole body is dumped.
+func (b *BodyDumper) Finish() {
+	if b.hex != nil {
+		b.hex.Close() // flush the last line
+	}
+	if b.truncated <= 0 {
+		return
 	}
+	b.dump.DumpTo([]byte(fmt.Sprintf("\r\n[truncated: %d bytes remaining]", b.truncated)), b.output)
+	b.truncated = 0
 }
 
 // GetResponseHeaderDumpers return Dumpers which need dump response header.
@@ -169,7 +268,7 @@ func (d *Dumper) DumpDefault(p []byte) {
 }
 
 func (d *Dumper) DumpRequestHeader(p []byte) {
-	d.DumpTo(p, d.RequestHeaderOutput())
+	d.DumpTo(d.redactHeader(p), d.RequestHeaderOutput())
 }
 
 func (d *Dumper) DumpRequestBody(p []byte) {
@@ -177,7 +276,29 @@ func (d *Dumper) DumpRequestBody(p []byte) {
 }
 
 func (d *Dumper) DumpResponseHeader(p []byte) {
-	d.DumpTo(p, d.ResponseHeaderOutput())
+	d.DumpTo(d.redactHeader(p), d.ResponseHeaderOutput())
+}
+
+var redacted = []byte("[REDACTED]")
+
+// redactHeader replaces the value of the header line with "[REDACTED]" if
+// the header is sensitive, p is expected to be a single header line like
+// "Authorization: xxx\r\n".
+func (d *Dumper) redactHeader(p []byte) []byte {
+	name, _, found := bytes.Cut(p, []byte(":"))
+	if !found {
+		return p
+	}
+	for _, h := range d.SensitiveHeaders() {
+		if strings.EqualFold(string(name), h) {
+			var line []byte
+			line = append(line, p[:len(name)+1]...)
+			line = append(line, ' ')
+			line = append(line, redacted...)
+			return append(line, "\r\n"...)
+		}
+	}
+	return p
 }
 
 func (d *Dumper) DumpResponseBody(p []byte) {
@@ -215,11 +336,17 @@ func GetDumpers(ctx context.Context, dump *Dumper) []*Dumper {
 	return dumps
 }
 
+// headResponseBodyNote is dumped as the response body of the HEAD request,
+// which must not contain a message body (RFC 9110, Section 9.3.2).
+var headResponseBodyNote = []byte("(response to HEAD request has no body)")
+
 func WrapResponseBodyIfNeeded(res *http.Response, req *http.Request, dump *Dumper) {
 	dumps := GetDumpers(req.Context(), dump)
 	for _, d := range dumps {
-		if d.ResponseBody() {
-			res.Body = d.WrapResponseBodyReadCloser(res.Body)
+		if d.ResponseBody() && req.Method == http.MethodHead {
+			d.DumpResponseBody(headResponseBodyNote)
+		} else if d.ResponseBody() {
+			res.Body = d.WrapResponseBodyReadCloser(res.Body, res.Header.Get("Content-Type"))
 		}
 	}
 }
@@ -7,9 +7,12 @@ const (
 	UserAgent            = "User-Agent"
 	Location             = "Location"
 	ContentType          = "Content-Type"
+	Accept               = "Accept"
 	PlainTextContentType = "text/plain; charset=utf-8"
 	JsonContentType      = "application/json; charset=utf-8"
 	XmlContentType       = "text/xml; charset=utf-8"
+	MsgpackContentType   = "application/msgpack"
+	ProtobufContentType  = "application/x-protobuf"
 	FormContentType      = "application/x-www-form-urlencoded"
 	WwwAuthenticate      = "WWW-Authenticate"
 	Authorization        = "Authorization"
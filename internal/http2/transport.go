@@ -1684,10 +1684,14 @@ func (cs *clientStream) writeRequestBody(req *http.Request, dumps []*dump.Dumper
 	}
 
 	writeData := cc.fr.WriteData
+	var bodyDumpers []*dump.BodyDumper
 	if len(dumps) > 0 {
+		for _, dump := range dumps {
+			bodyDumpers = append(bodyDumpers, dump.NewRequestBodyDumper(req.Header.Get("Content-Type")))
+		}
 		writeData = func(streamID uint32, endStream bool, data []byte) error {
-			for _, dump := range dumps {
-				dump.DumpRequestBody(data)
+			for _, bd := range bodyDumpers {
+				bd.Dump(data)
 			}
 			return cc.fr.WriteData(streamID, endStream, data)
 		}
@@ -1758,6 +1762,9 @@ func (cs *clientStream) writeRequestBody(req *http.Request, dumps []*dump.Dumper
 			return err
 		}
 	}
+	for _, bd := range bodyDumpers {
+		bd.Finish()
+	}
 
 	if sentEnd {
 		// Already sent END_STREAM (which implies we have no
@@ -345,7 +345,7 @@ func (c *client) roundTripOpt(req *http.Request, opt RoundTripOpt) (*http.Respon
 	return rsp, maybeReplaceError(rerr.err)
 }
 
-func (c *client) sendRequestBody(str Stream, body io.ReadCloser, dumps []*dump.Dumper) error {
+func (c *client) sendRequestBody(str Stream, body io.ReadCloser, contentType string, dumps []*dump.Dumper) error {
 	defer body.Close()
 	b := make([]byte, bodyCopyBufferSize)
 	writeData := func(data []byte) error {
@@ -354,10 +354,14 @@ func (c *client) sendRequestBody(str Stream, body io.ReadCloser, dumps []*dump.D
 		}
 		return nil
 	}
+	var bodyDumpers []*dump.BodyDumper
 	if len(dumps) > 0 {
+		for _, dump := range dumps {
+			bodyDumpers = append(bodyDumpers, dump.NewRequestBodyDumper(contentType))
+		}
 		writeData = func(data []byte) error {
-			for _, dump := range dumps {
-				dump.DumpRequestBody(data)
+			for _, bd := range bodyDumpers {
+				bd.Dump(data)
 			}
 			if _, err := str.Write(data); err != nil {
 				return err
@@ -372,6 +376,9 @@ func (c *client) sendRequestBody(str Stream, body io.ReadCloser, dumps []*dump.D
 				continue
 			}
 			if rerr == io.EOF {
+				for _, bd := range bodyDumpers {
+					bd.Finish()
+				}
 				for _, dump := range dumps {
 					dump.DumpDefault([]byte("\r\n\r\n"))
 				}
@@ -383,6 +390,9 @@ func (c *client) sendRequestBody(str Stream, body io.ReadCloser, dumps []*dump.D
 		}
 		if rerr != nil {
 			if rerr == io.EOF {
+				for _, bd := range bodyDumpers {
+					bd.Finish()
+				}
 				for _, dump := range dumps {
 					dump.DumpDefault([]byte("\r\n\r\n"))
 				}
@@ -425,7 +435,7 @@ func (c *client) doRequest(req *http.Request, conn quic.EarlyConnection, str qui
 					bodyDumps = append(bodyDumps, dump)
 				}
 			}
-			if err := c.sendRequestBody(hstr, req.Body, bodyDumps); err != nil {
+			if err := c.sendRequestBody(hstr, req.Body, req.Header.Get("Content-Type"), bodyDumps); err != nil {
 				c.opt.Debugf("error writing request: %s", err)
 			}
 			if !opt.DontCloseRequestStream {
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/imroc/req/v3/internal/transport"
@@ -156,6 +157,18 @@ func (r *RoundTripper) RoundTripOpt(req *http.Request, opt RoundTripOpt) (*http.
 	return rsp, err
 }
 
+// IsUnreachable reports whether the error indicates the QUIC connection
+// cannot be established, e.g. the UDP port is refused by ICMP unreachable
+// message or the handshake times out, which means the request has not been
+// sent, so it's safe to fall back to HTTP/2 or HTTP/1.1.
+func IsUnreachable(err error) bool {
+	var handshakeTimeout *quic.HandshakeTimeoutError
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EHOSTUNREACH) ||
+		errors.Is(err, syscall.ENETUNREACH) ||
+		errors.As(err, &handshakeTimeout)
+}
+
 // RoundTrip does a round trip.
 func (r *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	return r.RoundTripOpt(req, RoundTripOpt{})
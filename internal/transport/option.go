@@ -23,6 +23,12 @@ type Options struct {
 	// If Proxy is nil or returns a nil *URL, no proxy is used.
 	Proxy func(*http.Request) (*url.URL, error)
 
+	// NoProxy specifies the hosts which bypass the Proxy. Each entry
+	// is a hostname (optionally with port), a domain suffix with
+	// leading "." which matches the subdomains, an IP address, a CIDR
+	// network, or "*" which matches all hosts.
+	NoProxy []string
+
 	// OnProxyConnectResponse is called when the Transport gets an HTTP response from
 	// a proxy for a CONNECT request. It's called before the check for a 200 OK response.
 	// If it returns an error, the request fails with that error.
@@ -160,6 +166,17 @@ func (o Options) Clone() Options {
 	oo := o
 	if o.TLSClientConfig != nil {
 		oo.TLSClientConfig = o.TLSClientConfig.Clone()
+		// tls.Config.Clone is shallow, deep copy the fields which may be mutated.
+		oo.TLSClientConfig.Certificates = append([]tls.Certificate(nil), o.TLSClientConfig.Certificates...)
+		if o.TLSClientConfig.RootCAs != nil {
+			oo.TLSClientConfig.RootCAs = o.TLSClientConfig.RootCAs.Clone()
+		}
+		if o.TLSClientConfig.ClientCAs != nil {
+			oo.TLSClientConfig.ClientCAs = o.TLSClientConfig.ClientCAs.Clone()
+		}
+		oo.TLSClientConfig.NextProtos = append([]string(nil), o.TLSClientConfig.NextProtos...)
+		oo.TLSClientConfig.CipherSuites = append([]uint16(nil), o.TLSClientConfig.CipherSuites...)
+		oo.TLSClientConfig.CurvePreferences = append([]tls.CurveID(nil), o.TLSClientConfig.CurvePreferences...)
 	}
 	if o.Dump != nil {
 		oo.Dump = o.Dump.Clone()
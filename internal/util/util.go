@@ -18,6 +18,16 @@ func IsXMLType(ct string) bool {
 	return strings.Contains(ct, "xml")
 }
 
+// IsMsgpackType method is to check MessagePack content type or not
+func IsMsgpackType(ct string) bool {
+	return strings.Contains(ct, "msgpack")
+}
+
+// IsProtobufType method is to check Protobuf content type or not
+func IsProtobufType(ct string) bool {
+	return strings.Contains(ct, "protobuf")
+}
+
 // GetPointer return the pointer of the interface.
 func GetPointer(v interface{}) interface{} {
 	t := reflect.TypeOf(v)
@@ -1,9 +1,14 @@
 package req
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"time"
 )
 
 // Logger is the abstract logging interface, gives control to
@@ -14,6 +19,22 @@ type Logger interface {
 	Debugf(format string, v ...interface{})
 }
 
+// RequestLogger is an optional interface which can be implemented by Logger,
+// the LogRequest will be called with the structured request data before
+// each request is sent, body is nil if the request body is not in memory
+// (e.g. streaming upload).
+type RequestLogger interface {
+	LogRequest(req *http.Request, body []byte)
+}
+
+// ResponseLogger is an optional interface which can be implemented by Logger,
+// the LogResponse will be called with the structured response data after
+// each response is received, body is nil if the response body is not read
+// automatically (e.g. download or Client.DisableAutoReadResponse is called).
+type ResponseLogger interface {
+	LogResponse(resp *http.Response, body []byte, latency time.Duration)
+}
+
 // NewLogger create a Logger wraps the *log.Logger
 func NewLogger(output io.Writer, prefix string, flag int) Logger {
 	return &logger{l: log.New(output, prefix, flag)}
@@ -23,6 +44,17 @@ func NewLoggerFromStandardLogger(l *log.Logger) Logger {
 	return &logger{l: l}
 }
 
+// NewSlogLogger create a Logger wraps the *slog.Logger, the logs are emitted
+// with the corresponding levels, and it also implements RequestLogger and
+// ResponseLogger which emit the structured request and response data with
+// the debug level. The slog.Default() is used if l is nil.
+func NewSlogLogger(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &slogLogger{l: l}
+}
+
 func createDefaultLogger() Logger {
 	return NewLogger(os.Stdout, "", log.Ldate|log.Lmicroseconds)
 }
@@ -59,3 +91,59 @@ func (l *logger) output(level, format string, v ...interface{}) {
 	}
 	l.l.Printf(format, v...)
 }
+
+var (
+	_ Logger         = (*slogLogger)(nil)
+	_ RequestLogger  = (*slogLogger)(nil)
+	_ ResponseLogger = (*slogLogger)(nil)
+)
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (l *slogLogger) Errorf(format string, v ...interface{}) {
+	l.output(slog.LevelError, format, v...)
+}
+
+func (l *slogLogger) Warnf(format string, v ...interface{}) {
+	l.output(slog.LevelWarn, format, v...)
+}
+
+func (l *slogLogger) Debugf(format string, v ...interface{}) {
+	l.output(slog.LevelDebug, format, v...)
+}
+
+func (l *slogLogger) output(level slog.Level, format string, v ...interface{}) {
+	ctx := context.Background()
+	if !l.l.Enabled(ctx, level) {
+		return
+	}
+	msg := format
+	if len(v) > 0 {
+		msg = fmt.Sprintf(format, v...)
+	}
+	l.l.Log(ctx, level, msg, "logger", "req")
+}
+
+func (l *slogLogger) LogRequest(req *http.Request, body []byte) {
+	l.l.LogAttrs(req.Context(), slog.LevelDebug, "request",
+		slog.String("logger", "req"),
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Int("body_size", len(body)),
+	)
+}
+
+func (l *slogLogger) LogResponse(resp *http.Response, body []byte, latency time.Duration) {
+	ctx := context.Background()
+	if resp.Request != nil {
+		ctx = resp.Request.Context()
+	}
+	l.l.LogAttrs(ctx, slog.LevelDebug, "response",
+		slog.String("logger", "req"),
+		slog.Int("status_code", resp.StatusCode),
+		slog.Int("body_size", len(body)),
+		slog.Duration("latency", latency),
+	)
+}
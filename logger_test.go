@@ -3,7 +3,10 @@ package req
 import (
 	"bytes"
 	"log"
+	"log/slog"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/imroc/req/v3/internal/tests"
 )
@@ -29,3 +32,60 @@ func TestLoggerFromStandardLogger(t *testing.T) {
 	c.R().SetOutput(nil)
 	tests.AssertContains(t, buf.String(), "warn", true)
 }
+
+type structuredLogger struct {
+	Logger
+	reqURL    string
+	reqHeader http.Header
+	reqBody   string
+	respCode  int
+	respBody  string
+	latency   time.Duration
+}
+
+func (l *structuredLogger) LogRequest(req *http.Request, body []byte) {
+	l.reqURL = req.URL.Path
+	l.reqHeader = req.Header
+	l.reqBody = string(body)
+}
+
+func (l *structuredLogger) LogResponse(resp *http.Response, body []byte, latency time.Duration) {
+	l.respCode = resp.StatusCode
+	l.respBody = string(body)
+	l.latency = latency
+}
+
+func TestStructuredLogger(t *testing.T) {
+	l := &structuredLogger{Logger: NewLogger(new(bytes.Buffer), "", 0)}
+	resp, err := tc().SetLogger(l).R().SetBody("test").Post("/")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "/", l.reqURL)
+	tests.AssertEqual(t, "test", l.reqBody)
+	tests.AssertEqual(t, http.StatusOK, l.respCode)
+	tests.AssertEqual(t, "TestPost: text response", l.respBody)
+	tests.AssertEqual(t, true, l.latency > 0)
+}
+
+func TestSlogLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := tc().SetSlogLogger(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	c.SetProxyURL(":=\\<>ksfj&*&sf")
+	tests.AssertContains(t, buf.String(), "level=error msg=", true)
+	tests.AssertContains(t, buf.String(), "logger=req", true)
+	buf.Reset()
+	c.R().SetOutput(nil)
+	tests.AssertContains(t, buf.String(), `level=warn msg="nil io.writer is not allowed in setoutput"`, true)
+
+	buf.Reset()
+	resp, err := c.R().SetBody("test").Post("/")
+	assertSuccess(t, resp, err)
+	tests.AssertContains(t, buf.String(), "level=debug msg=request logger=req method=post", true)
+	tests.AssertContains(t, buf.String(), "level=debug msg=response logger=req status_code=200 body_size=23", true)
+
+	// the debug logs are not emitted if the level is not enabled.
+	buf.Reset()
+	c.SetSlogLogger(slog.New(slog.NewTextHandler(buf, nil)))
+	resp, err = c.R().Get("/")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "", buf.String())
+}
@@ -0,0 +1,130 @@
+package req
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type prometheusMetrics struct {
+	requests          *prometheus.CounterVec
+	duration          *prometheus.HistogramVec
+	requestBodyBytes  *prometheus.HistogramVec
+	responseBodyBytes *prometheus.HistogramVec
+	inFlight          prometheus.Gauge
+}
+
+// EnablePrometheusMetrics registers the Prometheus metrics of the requests
+// fired from the client to reg, and records each attempt of the requests:
+//
+//   - req_requests_total: counter of the requests, labeled by method, host and
+//     status_code ("error" if no response is received).
+//   - req_request_duration_seconds: histogram of the request duration, labeled
+//     by method and host.
+//   - req_request_body_bytes: histogram of the request body size, labeled by
+//     method and host, the request without body is not observed.
+//   - req_response_body_bytes: histogram of the response body size, labeled by
+//     method and host, the response whose size is unknown (e.g. the streamed
+//     body with DisableAutoReadResponse) is not observed.
+//   - req_inflight_requests: gauge of the requests in flight.
+//
+// All metrics have the "client" const label with the value clientName, so
+// multiple clients can be registered to the same reg with different names. The
+// metrics registered with the same name are reused. The global
+// prometheus.DefaultRegisterer is used if reg is nil.
+func (c *Client) EnablePrometheusMetrics(reg prometheus.Registerer, clientName string) *Client {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	constLabels := prometheus.Labels{"client": clientName}
+	sizeBuckets := prometheus.ExponentialBuckets(64, 4, 8)
+	m := &prometheusMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "req_requests_total",
+			Help:        "Total number of the HTTP requests.",
+			ConstLabels: constLabels,
+		}, []string{"method", "host", "status_code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "req_request_duration_seconds",
+			Help:        "Duration of the HTTP requests in seconds.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.DefBuckets,
+		}, []string{"method", "host"}),
+		requestBodyBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "req_request_body_bytes",
+			Help:        "Size of the HTTP request bodies in bytes.",
+			ConstLabels: constLabels,
+			Buckets:     sizeBuckets,
+		}, []string{"method", "host"}),
+		responseBodyBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "req_response_body_bytes",
+			Help:        "Size of the HTTP response bodies in bytes.",
+			ConstLabels: constLabels,
+			Buckets:     sizeBuckets,
+		}, []string{"method", "host"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "req_inflight_requests",
+			Help:        "Number of the HTTP requests in flight.",
+			ConstLabels: constLabels,
+		}),
+	}
+	m.requests = registerCollector(c, reg, m.requests)
+	m.duration = registerCollector(c, reg, m.duration)
+	m.requestBodyBytes = registerCollector(c, reg, m.requestBodyBytes)
+	m.responseBodyBytes = registerCollector(c, reg, m.responseBodyBytes)
+	m.inFlight = registerCollector(c, reg, m.inFlight)
+
+	wrapped := c.metrics != nil
+	c.metrics = m
+	if wrapped {
+		return c
+	}
+	return c.WrapRoundTripFunc(func(rt RoundTripper) RoundTripFunc {
+		return func(req *Request) (*Response, error) {
+			return req.client.metrics.roundTrip(rt, req)
+		}
+	})
+}
+
+// registerCollector registers collector to reg, and returns the existing one
+// if the same collector is already registered.
+func registerCollector[T prometheus.Collector](c *Client, reg prometheus.Registerer, collector T) T {
+	err := reg.Register(collector)
+	if err == nil {
+		return collector
+	}
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(T); ok {
+			return existing
+		}
+	}
+	c.log.Warnf("failed to register prometheus metrics: %v", err)
+	return collector
+}
+
+func (m *prometheusMetrics) roundTrip(rt RoundTripper, req *Request) (resp *Response, err error) {
+	m.inFlight.Inc()
+	start := time.Now()
+	resp, err = rt.RoundTrip(req)
+	m.inFlight.Dec()
+
+	host := req.URL.Host
+	m.duration.WithLabelValues(req.Method, host).Observe(time.Since(start).Seconds())
+	if n := len(req.Body); n > 0 {
+		m.requestBodyBytes.WithLabelValues(req.Method, host).Observe(float64(n))
+	}
+	statusCode := "error"
+	if resp != nil && resp.Response != nil {
+		statusCode = strconv.Itoa(resp.StatusCode)
+		if resp.body != nil {
+			m.responseBodyBytes.WithLabelValues(req.Method, host).Observe(float64(len(resp.body)))
+		} else if resp.ContentLength >= 0 {
+			m.responseBodyBytes.WithLabelValues(req.Method, host).Observe(float64(resp.ContentLength))
+		}
+	}
+	m.requests.WithLabelValues(req.Method, host, statusCode).Inc()
+	return
+}
@@ -0,0 +1,74 @@
+package req
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+// requestBodyBytesMetric returns the text exposition of req_request_body_bytes
+// with the single series whose observations are all within the first bucket.
+func requestBodyBytesMetric(clientName, method, host string, count int, sum float64) string {
+	var b strings.Builder
+	b.WriteString("# HELP req_request_body_bytes Size of the HTTP request bodies in bytes.\n")
+	b.WriteString("# TYPE req_request_body_bytes histogram\n")
+	labels := fmt.Sprintf(`client=%q,host=%q,method=%q`, clientName, host, method)
+	for _, le := range prometheus.ExponentialBuckets(64, 4, 8) {
+		fmt.Fprintf(&b, "req_request_body_bytes_bucket{%s,le=\"%g\"} %d\n", labels, le, count)
+	}
+	fmt.Fprintf(&b, "req_request_body_bytes_bucket{%s,le=\"+Inf\"} %d\n", labels, count)
+	fmt.Fprintf(&b, "req_request_body_bytes_sum{%s} %g\n", labels, sum)
+	fmt.Fprintf(&b, "req_request_body_bytes_count{%s} %d\n", labels, count)
+	return b.String()
+}
+
+func assertMetricCount(t *testing.T, reg prometheus.Gatherer, name string, expected int) {
+	n, err := testutil.GatherAndCount(reg, name)
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, expected, n)
+}
+
+func TestEnablePrometheusMetrics(t *testing.T) {
+	u, err := url.Parse(getTestServerURL())
+	tests.AssertNoError(t, err)
+	host := u.Host
+
+	reg := prometheus.NewRegistry()
+	c := tc().EnablePrometheusMetrics(reg, "test")
+	resp, err := c.R().SetBody("hello").Post("/")
+	assertSuccess(t, resp, err)
+	resp, err = c.R().Get("/bad-request")
+	tests.AssertNoError(t, err)
+
+	// another client is registered to the same registry with a different name.
+	other := tc().EnablePrometheusMetrics(reg, "other")
+	resp, err = other.R().Get("/")
+	assertSuccess(t, resp, err)
+
+	assertMetricCount(t, reg, "req_requests_total", 3)
+	tests.AssertEqual(t, float64(1), testutil.ToFloat64(c.metrics.requests.WithLabelValues("POST", host, "200")))
+	tests.AssertEqual(t, float64(1), testutil.ToFloat64(c.metrics.requests.WithLabelValues("GET", host, "400")))
+	tests.AssertEqual(t, float64(1), testutil.ToFloat64(other.metrics.requests.WithLabelValues("GET", host, "200")))
+
+	tests.AssertNoError(t, testutil.GatherAndCompare(reg,
+		strings.NewReader(requestBodyBytesMetric("test", "POST", host, 1, float64(len("hello")))),
+		"req_request_body_bytes"))
+	assertMetricCount(t, reg, "req_request_duration_seconds", 3)
+	assertMetricCount(t, reg, "req_response_body_bytes", 3)
+	tests.AssertEqual(t, float64(0), testutil.ToFloat64(c.metrics.inFlight))
+	tests.AssertEqual(t, float64(0), testutil.ToFloat64(other.metrics.inFlight))
+
+	// the metrics with the same name are reused.
+	c.EnablePrometheusMetrics(reg, "test")
+	resp, err = c.R().SetBody("hello").Post("/")
+	assertSuccess(t, resp, err)
+	tests.AssertNoError(t, testutil.GatherAndCompare(reg,
+		strings.NewReader(requestBodyBytesMetric("test", "POST", host, 2, float64(2*len("hello")))),
+		"req_request_body_bytes"))
+}
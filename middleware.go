@@ -2,6 +2,9 @@ package req
 
 import (
 	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -10,9 +13,12 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/imroc/req/v3/internal/header"
 	"github.com/imroc/req/v3/internal/util"
 )
@@ -23,6 +29,10 @@ type (
 
 	// ResponseMiddleware type is for response middleware, called after a response has been received
 	ResponseMiddleware func(client *Client, resp *Response) error
+
+	// RawRequestMiddleware type is for raw request middleware, called with the
+	// finalized http.Request right before it is sent
+	RawRequestMiddleware func(client *Client, req *http.Request) error
 )
 
 func createMultipartHeader(file *FileUpload, contentType string) textproto.MIMEHeader {
@@ -49,6 +59,9 @@ func createMultipartHeader(file *FileUpload, contentType string) textproto.MIMEH
 	if !util.IsStringEmpty(contentType) {
 		hdr.Set(header.ContentType, contentType)
 	}
+	if file.setContentLength && file.FileSize >= 0 {
+		hdr.Set("Content-Length", strconv.FormatInt(file.FileSize, 10))
+	}
 	return hdr
 }
 
@@ -122,34 +135,48 @@ func writeMultipartFormFile(w *multipart.Writer, file *FileUpload, r *Request) e
 	return err
 }
 
-func writeMultiPart(r *Request, w *multipart.Writer) {
+func writeMultiPart(r *Request, w *multipart.Writer) error {
 	defer w.Close() // close multipart to write tailer boundary
 	for k, vs := range r.FormData {
 		for _, v := range vs {
-			w.WriteField(k, v)
+			if err := w.WriteField(k, v); err != nil {
+				return err
+			}
 		}
 	}
 	for _, file := range r.uploadFiles {
-		writeMultipartFormFile(w, file, r)
+		if err := writeMultipartFormFile(w, file, r); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 func handleMultiPart(c *Client, r *Request) (err error) {
 	if r.forceChunkedEncoding {
-		pr, pw := io.Pipe()
+		// stream the multipart body without buffering it in memory, the
+		// body is written lazily when it is read.
+		mw := multipart.NewWriter(nil)
+		boundary := mw.Boundary()
 		r.GetBody = func() (io.ReadCloser, error) {
+			pr, pw := io.Pipe()
+			w := multipart.NewWriter(pw)
+			if err := w.SetBoundary(boundary); err != nil {
+				return nil, err
+			}
+			go func() {
+				// close pipe writer so that pipe reader could get EOF (or the error), and stop upload
+				pw.CloseWithError(writeMultiPart(r, w))
+			}()
 			return pr, nil
 		}
-		w := multipart.NewWriter(pw)
-		r.SetContentType(w.FormDataContentType())
-		go func() {
-			writeMultiPart(r, w)
-			pw.Close() // close pipe writer so that pipe reader could get EOF, and stop upload
-		}()
+		r.SetContentType(mw.FormDataContentType())
 	} else {
 		buf := new(bytes.Buffer)
 		w := multipart.NewWriter(buf)
-		writeMultiPart(r, w)
+		if err = writeMultiPart(r, w); err != nil {
+			return
+		}
 		r.GetBody = func() (io.ReadCloser, error) {
 			return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
 		}
@@ -159,9 +186,12 @@ func handleMultiPart(c *Client, r *Request) (err error) {
 	return
 }
 
+var errFormDataWithBody = errors.New("form data cannot be used with other request body")
+
 func handleFormData(r *Request) {
 	r.SetContentType(header.FormContentType)
 	r.SetBodyBytes([]byte(r.FormData.Encode()))
+	r.isFormBody = true
 }
 
 func handleMarshalBody(c *Client, r *Request) error {
@@ -179,6 +209,18 @@ func handleMarshalBody(c *Client, r *Request) error {
 				return err
 			}
 			r.SetBodyBytes(body)
+		} else if util.IsMsgpackType(ct) {
+			body, err := c.msgpackMarshal(r.marshalBody)
+			if err != nil {
+				return err
+			}
+			r.SetBodyBytes(body)
+		} else if msg, ok := r.marshalBody.(proto.Message); ok && util.IsProtobufType(ct) {
+			body, err := proto.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			r.SetBodyBytes(body)
 		} else {
 			body, err := c.jsonMarshal(r.marshalBody)
 			if err != nil {
@@ -208,13 +250,20 @@ func parseRequestBody(c *Client, r *Request) (err error) {
 		return handleMultiPart(c, r)
 	}
 
-	// handle form data
-	if len(c.FormData) > 0 {
-		r.SetFormDataFromValues(c.FormData)
-	}
-	if len(r.FormData) > 0 {
-		handleFormData(r)
-		return
+	// handle form data, the form data set at request-level cannot be used with
+	// other request body, while the common form data is just ignored.
+	if !r.isFormBody && (r.marshalBody != nil || r.Body != nil || r.GetBody != nil) {
+		if len(r.FormData) > 0 {
+			return errFormDataWithBody
+		}
+	} else {
+		if len(c.FormData) > 0 {
+			r.SetFormDataFromValues(c.FormData)
+		}
+		if len(r.FormData) > 0 {
+			handleFormData(r)
+			return
+		}
 	}
 
 	// handle marshal body
@@ -250,6 +299,10 @@ func unmarshalBody(c *Client, r *Response, v interface{}) (err error) {
 		return c.jsonUnmarshal(body, v)
 	} else if util.IsXMLType(ct) {
 		return c.xmlUnmarshal(body, v)
+	} else if util.IsMsgpackType(ct) {
+		return c.msgpackUnmarshal(body, v)
+	} else if msg, ok := v.(proto.Message); ok && util.IsProtobufType(ct) {
+		return proto.Unmarshal(body, msg)
 	} else {
 		if c.DebugLog {
 			c.log.Debugf("cannot determine the unmarshal function with %q Content-Type, default to json", ct)
@@ -368,15 +421,13 @@ func handleDownload(c *Client, r *Response) (err error) {
 		body = r.Body
 	}
 
+	if r.Request.atomicDownload {
+		return handleAtomicDownload(c, r, body)
+	}
+
 	var output io.Writer
 	if r.Request.outputFile != "" {
-		file := r.Request.outputFile
-		if c.outputDirectory != "" && !filepath.IsAbs(file) {
-			file = c.outputDirectory + string(filepath.Separator) + file
-		}
-
-		file = filepath.Clean(file)
-
+		file := outputFilePath(c, r.Request.outputFile)
 		if err = util.CreateDirectory(filepath.Dir(file)); err != nil {
 			return err
 		}
@@ -398,6 +449,103 @@ func handleDownload(c *Client, r *Response) (err error) {
 	return
 }
 
+func outputFilePath(c *Client, file string) string {
+	if c.outputDirectory != "" && !filepath.IsAbs(file) {
+		file = c.outputDirectory + string(filepath.Separator) + file
+	}
+	return filepath.Clean(file)
+}
+
+type progressWriter struct {
+	io.Writer
+	downloaded int64
+	total      int64
+	progress   ProgressFunc
+	lastTime   time.Time
+}
+
+func (w *progressWriter) Write(p []byte) (n int, err error) {
+	n, err = w.Writer.Write(p)
+	w.downloaded += int64(n)
+	if now := time.Now(); now.Sub(w.lastTime) >= 200*time.Millisecond {
+		w.lastTime = now
+		w.progress(w.downloaded, w.total)
+	}
+	return
+}
+
+// handleAtomicDownload writes the response body to the temporary file, which
+// is renamed to the output file after the download is completed. The partial
+// content is appended to the temporary file if the download is resumed and
+// the server responds with 206.
+func handleAtomicDownload(c *Client, r *Response, body io.ReadCloser) (err error) {
+	defer body.Close()
+	if !r.IsSuccessState() {
+		return nil
+	}
+	file := outputFilePath(c, r.Request.outputFile)
+	if err = util.CreateDirectory(filepath.Dir(file)); err != nil {
+		return err
+	}
+	tmpFile := file + ".tmp"
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	var offset int64
+	if r.StatusCode == http.StatusPartialContent {
+		if offset, err = contentRangeStart(r.Header.Get("Content-Range")); err != nil {
+			return err
+		}
+		info, err := os.Stat(tmpFile)
+		if err != nil {
+			return err
+		}
+		if info.Size() != offset {
+			return fmt.Errorf("cannot resume download: the size of %s is %d, but the server responds content range from %d", tmpFile, info.Size(), offset)
+		}
+		flag = os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(tmpFile, flag, 0644)
+	if err != nil {
+		return err
+	}
+	var output io.Writer = f
+	if progress := r.Request.downloadProgress; progress != nil {
+		total := int64(-1)
+		if r.ContentLength >= 0 {
+			total = offset + r.ContentLength
+		}
+		pw := &progressWriter{Writer: f, downloaded: offset, total: total, progress: progress, lastTime: time.Now()}
+		defer func() {
+			if err == nil {
+				progress(pw.downloaded, pw.total)
+			}
+		}()
+		output = pw
+	}
+	_, err = io.Copy(output, body)
+	r.setReceivedAt()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, file)
+}
+
+// contentRangeStart parses the start position of the "Content-Range" header,
+// e.g. "bytes 100-199/200".
+func contentRangeStart(contentRange string) (int64, error) {
+	rangeSpec, ok := strings.CutPrefix(contentRange, "bytes ")
+	if ok {
+		if start, _, ok := strings.Cut(rangeSpec, "-"); ok {
+			if n, err := strconv.ParseInt(start, 10, 64); err == nil {
+				return n, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid Content-Range header: %q", contentRange)
+}
+
 // generate URL
 func parseRequestURL(c *Client, r *Request) error {
 	tempURL := r.RawURL
@@ -425,7 +573,7 @@ func parseRequestURL(c *Client, r *Request) error {
 		}
 	}
 
-	// If RawURL is relative path then added c.BaseURL into
+	// If RawURL is relative path then added c.BaseURL and c.pathPrefix into
 	// the request URL otherwise Request.URL will be used as-is
 	if !reqURL.IsAbs() {
 		tempURL = reqURL.String()
@@ -433,7 +581,7 @@ func parseRequestURL(c *Client, r *Request) error {
 			tempURL = "/" + tempURL
 		}
 
-		reqURL, err = url.Parse(c.BaseURL + tempURL)
+		reqURL, err = url.Parse(c.BaseURL + c.pathPrefix + tempURL)
 		if err != nil {
 			return err
 		}
@@ -442,6 +590,9 @@ func parseRequestURL(c *Client, r *Request) error {
 	// Adding Query Param
 	query := make(url.Values)
 	for k, v := range c.QueryParams {
+		if r.isQueryParamRemoved(k) {
+			continue
+		}
 		for _, iv := range v {
 			query.Add(k, iv)
 		}
@@ -482,13 +633,46 @@ func parseRequestHeader(c *Client, r *Request) error {
 		r.Headers = make(http.Header)
 	}
 	for k, vs := range c.Headers {
-		if len(r.Headers[k]) == 0 {
+		if len(r.Headers[k]) == 0 && !r.isHeaderDeleted(k) {
 			r.Headers[k] = vs
 		}
 	}
 	return nil
 }
 
+func parseRequestTraceID(c *Client, r *Request) error {
+	if r.traceID == "" && r.autoTraceID {
+		id, err := newUUID()
+		if err != nil {
+			return err
+		}
+		r.traceID = id
+	}
+	if r.traceID == "" {
+		return nil
+	}
+	if r.Headers == nil {
+		r.Headers = make(http.Header)
+	}
+	name := c.traceIDHeader
+	if name == "" {
+		name = defaultTraceIDHeader
+	}
+	r.Headers.Set(name, r.traceID)
+	return nil
+}
+
+// newUUID generates a random (version 4) UUID.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := io.ReadFull(rand.Reader, b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
 func parseRequestCookie(c *Client, r *Request) error {
 	if len(c.Cookies) == 0 || r.RetryAttempt > 0 {
 		return nil
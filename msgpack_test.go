@@ -0,0 +1,60 @@
+package req
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/imroc/req/v3/internal/header"
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestMsgpack(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(header.ContentType, r.Header.Get(header.ContentType))
+		io.Copy(w, r.Body)
+	}))
+	defer ts.Close()
+
+	type User struct {
+		Name string `msgpack:"name"`
+		Age  int    `msgpack:"age"`
+	}
+	var user User
+	resp, err := C().R().
+		SetBodyMsgpackMarshal(&User{Name: "roc", Age: 18}).
+		SetSuccessResult(&user).
+		EnableDump().
+		Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, header.MsgpackContentType, resp.GetContentType())
+	tests.AssertEqual(t, User{Name: "roc", Age: 18}, user)
+	// the binary body is dumped in hex.
+	tests.AssertContains(t, resp.Dump(), "00000000  82 a4 6e 61 6d 65 a3 72  6f 63 a3 61 67 65 12     |..name.roc.age.|", true)
+
+	user = User{}
+	tests.AssertNoError(t, resp.UnmarshalMsgpack(&user))
+	tests.AssertEqual(t, "roc", user.Name)
+
+	// the marshal body is marshaled by msgpack according to the content type.
+	user = User{}
+	resp, err = C().R().
+		SetContentType(header.MsgpackContentType).
+		SetBody(map[string]interface{}{"name": "imroc"}).
+		Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertNoError(t, resp.Unmarshal(&user))
+	tests.AssertEqual(t, "imroc", user.Name)
+
+	var marshaled interface{}
+	c := C().SetMsgpackMarshal(func(v interface{}) ([]byte, error) {
+		marshaled = v
+		return msgpack.Marshal(v)
+	})
+	resp, err = c.R().SetBodyMsgpackMarshal("test").Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "test", marshaled)
+}
@@ -0,0 +1,70 @@
+package req
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+)
+
+// MultipartMixed parses the multipart/mixed response body, which is used by
+// batch APIs like Google Batch APIs, each part of the body is parsed as a
+// nested HTTP response with its own status line, headers and body. The nested
+// responses are returned in the order of the parts, and their bodies are read
+// into memory so that they can be read after the call.
+func (r *Response) MultipartMixed() ([]*http.Response, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	mediaType, params, err := mime.ParseMediaType(r.GetContentType())
+	if err != nil {
+		return nil, fmt.Errorf("multipart: invalid content type: %w", err)
+	}
+	if mediaType != "multipart/mixed" {
+		return nil, fmt.Errorf("multipart: unexpected content type %q", mediaType)
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, errors.New("multipart: no boundary in content type")
+	}
+	body, err := r.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+	var responses []*http.Response
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := mr.NextRawPart()
+		if err == io.EOF {
+			return responses, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp, err := readNestedResponse(part)
+		if err != nil {
+			return nil, fmt.Errorf("multipart: invalid response in part %d: %w", len(responses)+1, err)
+		}
+		responses = append(responses, resp)
+	}
+}
+
+// readNestedResponse reads the HTTP response in the part, the body is read
+// in memory since the part is invalid after the next part is read.
+func readNestedResponse(part *multipart.Part) (*http.Response, error) {
+	resp, err := http.ReadResponse(bufio.NewReader(part), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	return resp, nil
+}
@@ -0,0 +1,74 @@
+package req
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestMultipartMixed(t *testing.T) {
+	batch := strings.Join([]string{
+		"--batch_a=b",
+		"Content-Type: application/http",
+		"Content-ID: <response-item1>",
+		"",
+		"HTTP/1.1 200 OK",
+		"Content-Type: application/json",
+		"Content-Length: 11",
+		"",
+		`{"id":"1"}` + "\n",
+		"--batch_a=b",
+		"Content-Type: application/http",
+		"",
+		"HTTP/1.1 404 Not Found",
+		"Content-Type: multipart/mixed; boundary=nested",
+		"",
+		"--nested",
+		"",
+		"not the outer boundary",
+		"--nested--",
+		"--batch_a=b--",
+		"",
+	}, "\r\n")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/batch":
+			w.Header().Set("Content-Type", `multipart/mixed; boundary="batch_a=b"`)
+			w.Write([]byte(batch))
+		case "/no-boundary":
+			w.Header().Set("Content-Type", "multipart/mixed")
+		default:
+			w.Write([]byte("plain"))
+		}
+	}))
+	defer ts.Close()
+
+	c := C().SetBaseURL(ts.URL)
+	for _, r := range []*Request{c.R(), c.R().DisableAutoReadResponse()} {
+		resp, err := r.Get("/batch")
+		assertSuccess(t, resp, err)
+		responses, err := resp.MultipartMixed()
+		tests.AssertNoError(t, err)
+		tests.AssertEqual(t, 2, len(responses))
+
+		tests.AssertEqual(t, http.StatusOK, responses[0].StatusCode)
+		tests.AssertEqual(t, "application/json", responses[0].Header.Get("Content-Type"))
+		b, _ := io.ReadAll(responses[0].Body)
+		tests.AssertEqual(t, `{"id":"1"}`+"\n", string(b))
+
+		tests.AssertEqual(t, http.StatusNotFound, responses[1].StatusCode)
+		b, _ = io.ReadAll(responses[1].Body)
+		tests.AssertEqual(t, "--nested\r\n\r\nnot the outer boundary\r\n--nested--", string(b))
+	}
+
+	resp, _ := c.R().Get("/no-boundary")
+	_, err := resp.MultipartMixed()
+	tests.AssertErrorContains(t, err, "no boundary")
+	resp, _ = c.R().Get("/")
+	_, err = resp.MultipartMixed()
+	tests.AssertErrorContains(t, err, "unexpected content type")
+}
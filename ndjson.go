@@ -0,0 +1,88 @@
+package req
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/imroc/req/v3/internal/dump"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// DoNDJSON fires the request and reads the response body as newline-delimited
+// JSON (application/x-ndjson), each non-empty line is sent to the returned
+// value channel as soon as it is received. The line which is not valid JSON
+// is reported to the error channel without aborting the stream, while the
+// request error or the error response status ends the stream. Both channels
+// are closed when ctx is done or the body is exhausted, so the caller should
+// receive from both channels until they are closed.
+//
+// Use Request.EnableDump or Client.EnableDumpAll to dump the raw stream.
+func (r *Request) DoNDJSON(ctx context.Context) (<-chan json.RawMessage, <-chan error) {
+	values := make(chan json.RawMessage)
+	errs := make(chan error, 1)
+	if r.Method == "" {
+		r.Method = http.MethodGet
+	}
+	if d := r.Context().Value(dump.DumperKey); d != nil { // keep the request-level dumper
+		ctx = context.WithValue(ctx, dump.DumperKey, d)
+	}
+	if r.Headers.Get("Accept") == "" {
+		r.SetHeader("Accept", ndjsonContentType)
+	}
+	r.DisableAutoReadResponse()
+	go func() {
+		defer close(errs)
+		defer close(values)
+		sendErr := func(err error) bool {
+			select {
+			case errs <- err:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		if err := r.readNDJSON(ctx, values, sendErr); err != nil && ctx.Err() == nil {
+			sendErr(err)
+		}
+	}()
+	return values, errs
+}
+
+func (r *Request) readNDJSON(ctx context.Context, values chan<- json.RawMessage, sendErr func(err error) bool) error {
+	resp := r.Do(ctx)
+	if resp.Err != nil {
+		return resp.Err
+	}
+	defer resp.Body.Close()
+	if resp.IsErrorState() {
+		return fmt.Errorf("ndjson: bad response status: %s", resp.Status)
+	}
+	reader := bufio.NewReader(resp.Body)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if json.Valid(line) {
+				select {
+				case values <- json.RawMessage(line):
+				case <-ctx.Done():
+					return nil
+				}
+			} else if !sendErr(fmt.Errorf("ndjson: invalid JSON at line %d: %q", lineNum, line)) {
+				return nil
+			}
+		}
+		if err != nil { // io.EOF
+			return nil
+		}
+	}
+}
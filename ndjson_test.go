@@ -0,0 +1,60 @@
+package req
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func collectNDJSON(values <-chan json.RawMessage, errs <-chan error) (got []string, gotErrs []error) {
+	for values != nil || errs != nil {
+		select {
+		case v, ok := <-values:
+			if !ok {
+				values = nil
+				continue
+			}
+			got = append(got, string(v))
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			gotErrs = append(gotErrs, err)
+		}
+	}
+	return
+}
+
+func TestDoNDJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Accept"))
+		for _, line := range []string{`{"id":1}` + "\n", "\n", "not json\n", `{"id":2}` + "\r\n", `[3]`} {
+			w.Write([]byte(line))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	buf := new(bytes.Buffer)
+	values, errs := C().R().EnableDumpTo(buf).SetURL(server.URL).DoNDJSON(context.Background())
+	got, gotErrs := collectNDJSON(values, errs)
+	tests.AssertEqual(t, []string{`{"id":1}`, `{"id":2}`, `[3]`}, got)
+	tests.AssertEqual(t, 1, len(gotErrs))
+	tests.AssertErrorContains(t, gotErrs[0], "invalid JSON at line 3")
+	tests.AssertContains(t, buf.String(), "content-type: application/x-ndjson", true)
+	tests.AssertContains(t, buf.String(), "not json", true)
+}
+
+func TestDoNDJSONBadStatus(t *testing.T) {
+	values, errs := tc().R().SetURL("/bad-request").DoNDJSON(context.Background())
+	got, gotErrs := collectNDJSON(values, errs)
+	tests.AssertEqual(t, 0, len(got))
+	tests.AssertEqual(t, 1, len(gotErrs))
+	tests.AssertErrorContains(t, gotErrs[0], "400 Bad Request")
+}
@@ -0,0 +1,129 @@
+package req
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	urlpkg "net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imroc/req/v3/internal/header"
+	"github.com/imroc/req/v3/internal/util"
+)
+
+// oauth2ExpiryDelta is how much earlier a token is considered expired
+// than its actual expiration time, so the token can be refreshed before
+// it is rejected by the server.
+const oauth2ExpiryDelta = 10 * time.Second
+
+// oauth2ClientCredentials fetches and caches the token of OAuth2
+// client credentials flow, it is safe for concurrent use.
+type oauth2ClientCredentials struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (o *oauth2ClientCredentials) valid() bool {
+	if o.accessToken == "" {
+		return false
+	}
+	return o.expiry.IsZero() || time.Now().Add(oauth2ExpiryDelta).Before(o.expiry)
+}
+
+// token returns the cached token, fetch a new one if it is missing or
+// about to expire. If staleToken is not empty, the token is refreshed
+// unless it has already been refreshed by another goroutine.
+func (o *oauth2ClientCredentials) token(ctx context.Context, c *Client, staleToken string) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.valid() && (staleToken == "" || staleToken != o.accessToken) {
+		return o.accessToken, nil
+	}
+	return o.refresh(ctx, c)
+}
+
+func (o *oauth2ClientCredentials) refresh(ctx context.Context, c *Client) (string, error) {
+	data := urlpkg.Values{}
+	data.Set("grant_type", "client_credentials")
+	if len(o.scopes) > 0 {
+		data.Set("scope", strings.Join(o.scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(header.ContentType, header.FormContentType)
+	req.Header.Set(header.Authorization, util.BasicAuthHeaderValue(urlpkg.QueryEscape(o.clientID), urlpkg.QueryEscape(o.clientSecret)))
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("oauth2: cannot fetch token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("oauth2: cannot fetch token: %w", err)
+	}
+	if code := resp.StatusCode; code < 200 || code > 299 {
+		return "", fmt.Errorf("oauth2: cannot fetch token: %s, response: %s", resp.Status, body)
+	}
+	var tr oauth2TokenResponse
+	if err = c.jsonUnmarshal(body, &tr); err != nil {
+		return "", fmt.Errorf("oauth2: cannot parse token: %w", err)
+	}
+	if tr.AccessToken == "" {
+		return "", fmt.Errorf("oauth2: server response missing access_token")
+	}
+	o.accessToken = tr.AccessToken
+	if tr.ExpiresIn > 0 {
+		o.expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	} else {
+		o.expiry = time.Time{}
+	}
+	if c.DebugLog {
+		c.log.Debugf("<oauth2> token refreshed, expires at %v", o.expiry)
+	}
+	return o.accessToken, nil
+}
+
+// oauth2RoundTrip injects the bearer token into the request, refreshes the
+// token and retries the request once if the server responds with 401.
+func oauth2RoundTrip(rt RoundTripper) RoundTripFunc {
+	return func(req *Request) (resp *Response, err error) {
+		o := req.client.oauth2
+		if o == nil {
+			return rt.RoundTrip(req)
+		}
+		token, err := o.token(req.Context(), req.client, "")
+		if err != nil {
+			return req.newErrorResponse(err), err
+		}
+		req.SetBearerAuthToken(token)
+		resp, err = rt.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return
+		}
+		token, err = o.token(req.Context(), req.client, token)
+		if err != nil {
+			resp.Err = err
+			return
+		}
+		resp.Body.Close()
+		req.SetBearerAuthToken(token)
+		return rt.RoundTrip(req)
+	}
+}
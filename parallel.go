@@ -0,0 +1,88 @@
+package req
+
+import (
+	"context"
+	"sync"
+)
+
+// ParallelResult is the result of a request sent by Client.Parallel.
+type ParallelResult struct {
+	Request  *Request
+	Response *Response
+	Error    error
+}
+
+// Parallel sends the requests with the client concurrently, at most n requests
+// are in flight at the same time (unlimited if n <= 0), and returns the results
+// in the same order as the requests regardless of the completion order. The
+// requests go through the client's rate limiter, circuit breaker, retry and
+// middlewares as usual.
+//
+// ctx is used as the context of the requests which have no context set by
+// Request.SetContext, the requests which are not sent yet when ctx is done
+// fail with the ctx error.
+//
+// For Example:
+//
+//	results := client.Parallel(ctx, 5,
+//		client.R().SetURL("https://example.com/a"),
+//		client.R().SetMethod(http.MethodPost).SetURL("https://example.com/b"),
+//	)
+//	for _, result := range results {
+//		if result.Error != nil {
+//			// handle error
+//		}
+//	}
+func (c *Client) Parallel(ctx context.Context, n int, reqs ...*Request) []*ParallelResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if n <= 0 || n > len(reqs) {
+		n = len(reqs)
+	}
+	results := make([]*ParallelResult, len(reqs))
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for i, r := range reqs {
+		results[i] = &ParallelResult{Request: r}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Error = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(result *ParallelResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			r := result.Request
+			r.SetClient(c)
+			if r.ctx == nil {
+				r.ctx = ctx
+			}
+			result.Response = r.Do()
+			result.Error = result.Response.Err
+		}(results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+// MultiGet sends GET requests to the urls concurrently with Parallel, and
+// returns the responses and errors in the same order as the urls. The number
+// of requests in flight is only limited by Client.SetMaxInFlightRequests if
+// it is set.
+func (c *Client) MultiGet(ctx context.Context, urls ...string) ([]*Response, []error) {
+	reqs := make([]*Request, len(urls))
+	for i, url := range urls {
+		reqs[i] = c.Get(url)
+	}
+	resps := make([]*Response, len(urls))
+	errs := make([]error, len(urls))
+	for i, result := range c.Parallel(ctx, 0, reqs...) {
+		resps[i], errs[i] = result.Response, result.Error
+	}
+	return resps, errs
+}
@@ -0,0 +1,91 @@
+package req
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestParallel(t *testing.T) {
+	var inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		// the earlier request completes later.
+		i, _ := strconv.Atoi(r.URL.Query().Get("i"))
+		time.Sleep(time.Duration(10-i) * 5 * time.Millisecond)
+		w.Write([]byte(r.URL.Query().Get("i")))
+	}))
+	defer ts.Close()
+
+	c := C()
+	var reqs []*Request
+	for i := 0; i < 10; i++ {
+		reqs = append(reqs, c.R().SetURL(fmt.Sprintf("%s?i=%d", ts.URL, i)))
+	}
+	reqs = append(reqs, c.R().SetURL("http://invalid url"))
+	results := c.Parallel(context.Background(), 3, reqs...)
+	tests.AssertEqual(t, len(reqs), len(results))
+	for i, result := range results[:10] {
+		tests.AssertEqual(t, reqs[i], result.Request)
+		tests.AssertNoError(t, result.Error)
+		tests.AssertEqual(t, strconv.Itoa(i), result.Response.String())
+	}
+	tests.AssertNotNil(t, results[10].Error)
+	tests.AssertEqual(t, true, maxInFlight <= 3)
+
+	// the requests which are not sent yet fail after ctx is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = c.Parallel(ctx, 1, c.R().SetURL(ts.URL), c.R().SetURL(ts.URL))
+	for _, result := range results {
+		tests.AssertErrorContains(t, result.Error, "context canceled")
+	}
+}
+
+func TestMultiGet(t *testing.T) {
+	var inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		tests.AssertEqual(t, http.MethodGet, r.Method)
+		w.Write([]byte(r.URL.Query().Get("i")))
+	}))
+	defer ts.Close()
+
+	c := C().SetMaxInFlightRequests(2)
+	var urls []string
+	for i := 0; i < 6; i++ {
+		urls = append(urls, fmt.Sprintf("%s?i=%d", ts.URL, i))
+	}
+	urls = append(urls, "http://invalid url")
+	resps, errs := c.MultiGet(context.Background(), urls...)
+	tests.AssertEqual(t, len(urls), len(resps))
+	tests.AssertEqual(t, len(urls), len(errs))
+	for i := 0; i < 6; i++ {
+		tests.AssertNoError(t, errs[i])
+		tests.AssertEqual(t, strconv.Itoa(i), resps[i].String())
+	}
+	tests.AssertNotNil(t, errs[6])
+	tests.AssertEqual(t, true, maxInFlight <= 2)
+}
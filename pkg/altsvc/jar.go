@@ -2,7 +2,7 @@ package altsvc
 
 // Jar is a container of AltSvc.
 type Jar interface {
-	// SetAltSvc store the AltSvc.
+	// SetAltSvc store the AltSvc, remove the stored AltSvc if as is nil.
 	SetAltSvc(addr string, as *AltSvc)
 	// GetAltSvc get the AltSvc.
 	GetAltSvc(addr string) *AltSvc
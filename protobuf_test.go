@@ -0,0 +1,54 @@
+package req
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/imroc/req/v3/internal/header"
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestProtobuf(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(header.ContentType, r.Header.Get(header.ContentType))
+		io.Copy(w, r.Body)
+	}))
+	defer ts.Close()
+
+	msg := &wrapperspb.StringValue{}
+	resp, err := C().R().
+		SetBodyProtobuf(wrapperspb.String("roc")).
+		SetSuccessResult(msg).
+		EnableDump().
+		Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, header.ProtobufContentType, resp.GetContentType())
+	tests.AssertEqual(t, "roc", msg.GetValue())
+	// the binary body is dumped in hex.
+	tests.AssertContains(t, resp.Dump(), "00000000  0a 03 72 6f 63                                    |..roc|", true)
+
+	msg = &wrapperspb.StringValue{}
+	tests.AssertNoError(t, resp.UnmarshalProtobuf(msg))
+	tests.AssertEqual(t, "roc", msg.GetValue())
+
+	// the output of prototext is unstable on purpose, so compare it after
+	// removing the spaces.
+	text, err := resp.DumpProtobuf(msg.ProtoReflect().Descriptor())
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, "value:\"roc\"", strings.Join(strings.Fields(text), ""))
+
+	// the marshal body is marshaled by protobuf according to the content type.
+	msg = &wrapperspb.StringValue{}
+	resp, err = C().R().
+		SetContentType(header.ProtobufContentType).
+		SetBody(wrapperspb.String("imroc")).
+		Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertNoError(t, resp.Unmarshal(msg))
+	tests.AssertEqual(t, "imroc", msg.GetValue())
+}
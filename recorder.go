@@ -0,0 +1,161 @@
+package req
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Recorder records the responses and replays them, which is used by
+// Client.RecordRequests and Client.ReplayRequests for cassette-style testing,
+// it must be safe for concurrent use.
+type Recorder interface {
+	// Record records the response of the request, the response body can be
+	// read freely since it is buffered in memory.
+	Record(req *http.Request, resp *http.Response) error
+	// Replay returns the recorded response of the request, and whether it
+	// is recorded.
+	Replay(req *http.Request) (*http.Response, bool, error)
+}
+
+// ErrNoRecordedResponse is returned in replay mode if there is no recorded
+// response for the request.
+var ErrNoRecordedResponse = errors.New("no recorded response")
+
+// RecordRequests enables the record mode, the requests are sent as usual and
+// the responses are recorded by the Recorder, which can be replayed by
+// ReplayRequests later. The response body is read in memory before recording,
+// so it's not suitable for the streaming response. Set to nil to disable.
+func (c *Client) RecordRequests(r Recorder) *Client {
+	c.recorder = r
+	c.replayRequests = false
+	return c
+}
+
+// ReplayRequests enables the replay mode, the responses recorded by the Recorder
+// are returned without hitting the network, ErrNoRecordedResponse is returned if
+// the response of the request is not recorded. The transport is not involved in
+// the replay mode, so the dump does not work. Set to nil to disable.
+func (c *Client) ReplayRequests(r Recorder) *Client {
+	c.recorder = r
+	c.replayRequests = r != nil
+	return c
+}
+
+func (c *Client) doRecorder(req *http.Request) (*http.Response, error) {
+	if c.replayRequests {
+		resp, ok, err := c.recorder.Replay(req)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %s %s", ErrNoRecordedResponse, req.Method, req.URL)
+		}
+		return resp, nil
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err = c.recorder.Record(req, resp); err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+type recordedResponse struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body,omitempty"`
+	// BodyBase64 is the body which is not valid UTF-8.
+	BodyBase64 []byte `json:"bodyBase64,omitempty"`
+}
+
+type fileRecorder struct {
+	dir string
+}
+
+// NewFileRecorder creates a Recorder which stores each response as a JSON file
+// in the dir, the file is named by the hash of the request method and url, so
+// the latest response of the same method and url is replayed.
+func NewFileRecorder(dir string) Recorder {
+	return &fileRecorder{dir: dir}
+}
+
+func (r *fileRecorder) filename(req *http.Request) string {
+	h := sha256.Sum256([]byte(req.Method + " " + req.URL.String()))
+	return filepath.Join(r.dir, hex.EncodeToString(h[:])+".json")
+}
+
+func (r *fileRecorder) Record(req *http.Request, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	recorded := &recordedResponse{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
+	if utf8.Valid(body) {
+		recorded.Body = string(body)
+	} else {
+		recorded.BodyBase64 = body
+	}
+	b, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(r.dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.filename(req), b, 0644)
+}
+
+func (r *fileRecorder) Replay(req *http.Request) (*http.Response, bool, error) {
+	b, err := os.ReadFile(r.filename(req))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	recorded := new(recordedResponse)
+	if err = json.Unmarshal(b, recorded); err != nil {
+		return nil, false, err
+	}
+	body := recorded.BodyBase64
+	if body == nil {
+		body = []byte(recorded.Body)
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(recorded.StatusCode) + " " + http.StatusText(recorded.StatusCode),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, true, nil
+}
@@ -0,0 +1,57 @@
+package req
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestRecordReplayRequests(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		switch r.URL.Path {
+		case "/binary":
+			w.Write([]byte{0xff, 0xfe, 0x00})
+		default:
+			w.Header().Set("X-Path", r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("hello " + r.Method))
+		}
+	}))
+	defer ts.Close()
+
+	recorder := NewFileRecorder(t.TempDir())
+	c := C().SetBaseURL(ts.URL).RecordRequests(recorder)
+	resp, err := c.R().Post("/hello")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, "hello POST", resp.String())
+	resp, err = c.R().Get("/binary")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, 2, hits)
+
+	c.ReplayRequests(recorder)
+	resp, err = c.R().Post("/hello")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, http.StatusCreated, resp.StatusCode)
+	tests.AssertEqual(t, "201 Created", resp.Status)
+	tests.AssertEqual(t, "/hello", resp.GetHeader("X-Path"))
+	tests.AssertEqual(t, "hello POST", resp.String())
+	resp, err = c.R().Get("/binary")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, []byte{0xff, 0xfe, 0x00}, resp.Bytes())
+	tests.AssertEqual(t, 2, hits)
+
+	// the method is a part of the key.
+	_, err = c.R().Get("/hello")
+	tests.AssertEqual(t, true, errors.Is(err, ErrNoRecordedResponse))
+	tests.AssertEqual(t, 2, hits)
+
+	resp, err = c.ReplayRequests(nil).R().Get("/hello")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, "hello GET", resp.String())
+	tests.AssertEqual(t, 3, hits)
+}
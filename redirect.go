@@ -8,7 +8,10 @@ import (
 	"strings"
 )
 
-// RedirectPolicy represents the redirect policy for Client.
+// RedirectPolicy represents the redirect policy for Client, req is the upcoming
+// redirected request and via is the requests made already, oldest first. It
+// returns error to stop redirecting, and can also modify the headers of req
+// which will be sent, see AlwaysCopyHeaderRedirectPolicy.
 type RedirectPolicy func(req *http.Request, via []*http.Request) error
 
 // MaxRedirectPolicy specifies the max number of redirect
@@ -54,6 +54,9 @@ type FileUpload struct {
 	// requires `Content-Disposition` parameters more than just
 	// "name" and "filename".
 	ExtraContentDisposition *ContentDisposition
+
+	// set the `Content-Length` of the part to FileSize if true.
+	setContentLength bool
 }
 
 // UploadInfo is the information for each UploadCallback call.
@@ -84,6 +87,11 @@ type DownloadInfo struct {
 // response body download.
 type DownloadCallback func(info DownloadInfo)
 
+// ProgressFunc is the callback which will be invoked during file download,
+// downloaded is the size of the downloaded content, and total is the size of
+// the whole file, which is -1 if the "Content-Length" is unknown.
+type ProgressFunc func(downloaded, total int64)
+
 func cloneSlice[T any](s []T) []T {
 	if len(s) == 0 {
 		return nil
@@ -1,6 +1,7 @@
 package req
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -20,13 +21,17 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 	"unsafe"
 )
 
 func tc() *Client {
-	return C().
-		SetBaseURL(getTestServerURL()).
-		EnableInsecureSkipVerify()
+	c := C().SetBaseURL(getTestServerURL())
+	// skip the warning of EnableInsecureSkipVerify
+	c.updateTLSClientConfig(func(config *tls.Config) {
+		config.InsecureSkipVerify = true
+	})
+	return c
 }
 
 var testDataPath string
@@ -48,7 +53,7 @@ func handleHTTP(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		handleGet(w, r)
-	case http.MethodPost:
+	case http.MethodPost, http.MethodPatch:
 		handlePost(w, r)
 	}
 }
@@ -258,6 +263,10 @@ func handleGet(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`This is a chunked body`))
 	case "/host-header":
 		w.Write([]byte(r.Host))
+	case "/sleep":
+		d, _ := time.ParseDuration(r.URL.Query().Get("d"))
+		time.Sleep(d)
+		w.Write([]byte("wake up"))
 	case "/json":
 		r.ParseForm()
 		if r.FormValue("type") != "no" {
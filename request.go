@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/imroc/req/v3/internal/dump"
 	"github.com/imroc/req/v3/internal/header"
@@ -44,6 +45,8 @@ type Request struct {
 	URL *urlpkg.URL
 
 	isMultiPart              bool
+	isFormBody               bool
+	singleFlight             bool
 	disableAutoReadResponse  bool
 	forceChunkedEncoding     bool
 	isSaveResponse           bool
@@ -55,20 +58,30 @@ type Request struct {
 	downloadCallback         DownloadCallback
 	downloadCallbackInterval time.Duration
 	unReplayableBody         io.ReadCloser
+	streamBodySize           int64
 	retryOption              *retryOption
 	bodyReadCloser           io.ReadCloser
 	dumpOptions              *DumpOptions
 	marshalBody              interface{}
 	ctx                      context.Context
+	timeout                  time.Duration
 	uploadFiles              []*FileUpload
 	uploadReader             []io.ReadCloser
 	outputFile               string
+	atomicDownload           bool
+	downloadProgress         ProgressFunc
 	output                   io.Writer
 	trace                    *clientTrace
 	dumpBuffer               *bytes.Buffer
 	responseReturnTime       time.Time
 	afterResponse            []ResponseMiddleware
 	contextData              map[string]any
+	middlewares              []Middleware
+	expectedStatus           []int
+	deletedHeaders           []string
+	removedQueryParams       []string
+	traceID                  string
+	autoTraceID              bool
 }
 
 type GetContentFunc func() (io.ReadCloser, error)
@@ -156,12 +169,40 @@ func (r *Request) HeaderToString() string {
 	return convertHeaderToString(r.Headers)
 }
 
+// SetMethod set the HTTP method for request, which can be any method including
+// the custom ones, the request is fired by Do.
+func (r *Request) SetMethod(method string) *Request {
+	r.Method = method
+	return r
+}
+
+// ExpectStatus set the expected status codes of the response, if the status
+// code is not one of them, the request returns *StatusMismatchError, which
+// keeps the test code flat.
+func (r *Request) ExpectStatus(codes ...int) *Request {
+	r.expectedStatus = append(r.expectedStatus, codes...)
+	return r
+}
+
+// ExpectStatusOK is a shortcut for ExpectStatus(http.StatusOK).
+func (r *Request) ExpectStatusOK() *Request {
+	return r.ExpectStatus(http.StatusOK)
+}
+
 // SetURL set the url for request.
 func (r *Request) SetURL(url string) *Request {
 	r.RawURL = url
 	return r
 }
 
+// EnableSingleFlight enables the deduplication of the request regardless of
+// its method if Client.EnableSingleFlight is called, the concurrent requests
+// with the same method and url share the response.
+func (r *Request) EnableSingleFlight() *Request {
+	r.singleFlight = true
+	return r
+}
+
 // SetFormDataFromValues set the form data from url.Values, will not
 // been used if request method does not allow payload.
 func (r *Request) SetFormDataFromValues(data urlpkg.Values) *Request {
@@ -176,6 +217,21 @@ func (r *Request) SetFormDataFromValues(data urlpkg.Values) *Request {
 	return r
 }
 
+// AddFormData add a form field for the request, the value is appended to
+// the existing values of the key, will not been used if request method does
+// not allow payload. The request fails with an error if the form data is
+// mixed with other request body, e.g. SetBodyJsonMarshal.
+//
+// It follows the Set/Add naming of the other form data methods, a method named
+// FormData is impossible since it's the field of Request.
+func (r *Request) AddFormData(key, value string) *Request {
+	if r.FormData == nil {
+		r.FormData = urlpkg.Values{}
+	}
+	r.FormData.Add(key, value)
+	return r
+}
+
 // SetFormData set the form data from a map, will not been used
 // if request method does not allow payload.
 func (r *Request) SetFormData(data map[string]string) *Request {
@@ -201,7 +257,9 @@ func (r *Request) SetFormDataAnyType(data map[string]interface{}) *Request {
 	return r
 }
 
-// SetCookies set http cookies for the request.
+// SetCookies set http cookies for the request, which are added to the `Cookie`
+// header directly without the cookie jar, so they are sent even if the cookie
+// jar is disabled, and are not stored to the cookie jar.
 func (r *Request) SetCookies(cookies ...*http.Cookie) *Request {
 	r.Cookies = append(r.Cookies, cookies...)
 	return r
@@ -294,6 +352,36 @@ func (r *Request) SetFile(paramName, filePath string) *Request {
 	})
 }
 
+// SetFormFile set up a multipart form from the opened file to upload, the
+// filename is the base name of the file, the `Content-Length` of the part is
+// set to the file size if it's a regular file, and the file is closed after
+// upload. It's rewound, or reopened by its name if it has been closed, to
+// upload again if the request is retried.
+func (r *Request) SetFormFile(paramName string, file *os.File) *Request {
+	fileInfo, err := file.Stat()
+	if err != nil {
+		r.client.log.Errorf("failed to stat file %s: %v", file.Name(), err)
+		r.appendError(err)
+		return r
+	}
+	filePath := file.Name()
+	return r.SetFileUpload(FileUpload{
+		ParamName: paramName,
+		FileName:  filepath.Base(filePath),
+		GetFileContent: func() (io.ReadCloser, error) {
+			if r.RetryAttempt > 0 {
+				if _, err := file.Seek(0, io.SeekStart); err == nil {
+					return file, nil
+				}
+				return os.Open(filePath)
+			}
+			return file, nil
+		},
+		FileSize:         fileInfo.Size(),
+		setContentLength: fileInfo.Mode().IsRegular(),
+	})
+}
+
 var (
 	errMissingParamName   = errors.New("missing param name in multipart file upload")
 	errMissingFileName    = errors.New("missing filename in multipart file upload")
@@ -415,6 +503,20 @@ func (r *Request) SetBasicAuth(username, password string) *Request {
 	return r.SetHeader(header.Authorization, util.BasicAuthHeaderValue(username, password))
 }
 
+// SetBasicAuthFromEnv set basic auth for the request with the username and
+// password read from the environment variables userEnv and passEnv when it's
+// called, a warning is logged if any of them is empty.
+func (r *Request) SetBasicAuthFromEnv(userEnv, passEnv string) *Request {
+	username, password := os.Getenv(userEnv), os.Getenv(passEnv)
+	if username == "" {
+		r.client.log.Warnf("environment variable %s for basic auth username is empty", userEnv)
+	}
+	if password == "" {
+		r.client.log.Warnf("environment variable %s for basic auth password is empty", passEnv)
+	}
+	return r.SetBasicAuth(username, password)
+}
+
 // SetDigestAuth sets the Digest Access auth scheme for the HTTP request. If a server responds with 401 and sends a
 // Digest challenge in the WWW-Authenticate Header, the request will be resent with the appropriate Authorization Header.
 //
@@ -432,13 +534,22 @@ func (r *Request) SetDigestAuth(username, password string) *Request {
 	return r
 }
 
+// Use adds middlewares which wrap the execution of the request, they are
+// executed in the order they are added, and run after the middlewares added
+// by Client.Use.
+func (r *Request) Use(middlewares ...Middleware) *Request {
+	r.middlewares = append(r.middlewares, middlewares...)
+	return r
+}
+
 // OnAfterResponse add a response middleware which hooks after response received.
 func (r *Request) OnAfterResponse(m ResponseMiddleware) *Request {
 	r.afterResponse = append(r.afterResponse, m)
 	return r
 }
 
-// SetHeaders set headers from a map for the request.
+// SetHeaders set headers from a map for the request, which override the
+// common headers with the same key set at the client level.
 func (r *Request) SetHeaders(hdrs map[string]string) *Request {
 	for k, v := range hdrs {
 		r.SetHeader(k, v)
@@ -455,6 +566,48 @@ func (r *Request) SetHeader(key, value string) *Request {
 	return r
 }
 
+// DelHeader deletes the header of the request, including the common header
+// set at the client level (e.g. by Client.SetCommonHeader), which is not sent
+// with the request.
+func (r *Request) DelHeader(key string) *Request {
+	r.Headers.Del(key)
+	delete(r.Headers, key) // non-canonical key
+	r.deletedHeaders = append(r.deletedHeaders, key)
+	return r
+}
+
+// isHeaderDeleted reports whether the header is deleted by DelHeader.
+func (r *Request) isHeaderDeleted(key string) bool {
+	for _, deleted := range r.deletedHeaders {
+		if strings.EqualFold(deleted, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTraceID set the trace ID of the request, which is sent in the header set
+// by Client.SetTraceIDHeader ("X-Request-ID" by default), so it's included in
+// the dump and the request passed to the RequestLogger.
+func (r *Request) SetTraceID(id string) *Request {
+	r.traceID = id
+	return r
+}
+
+// EnableAutoTraceID generates a random UUID as the trace ID of the request if
+// it's not set by SetTraceID, the same trace ID is sent in all retry attempts,
+// see SetTraceID.
+func (r *Request) EnableAutoTraceID() *Request {
+	r.autoTraceID = true
+	return r
+}
+
+// GetTraceID returns the trace ID of the request, which is empty if it's
+// neither set by SetTraceID nor generated before the request is sent.
+func (r *Request) GetTraceID() string {
+	return r.traceID
+}
+
 // SetHeadersNonCanonical set headers from a map for the request which key is a
 // non-canonical key (keep case unchanged), only valid for HTTP/1.1.
 func (r *Request) SetHeadersNonCanonical(hdrs map[string]string) *Request {
@@ -525,6 +678,38 @@ func (r *Request) SetOutputFile(file string) *Request {
 	return r
 }
 
+// DownloadToFile set the file that response Body will be downloaded to, the
+// content is written to the temporary file with ".tmp" suffix, which is renamed
+// to the file after the download is completed successfully, the response Body
+// is not downloaded if the response is not successful. The optional progress
+// is invoked at least every 200ms during download, and after the download is
+// completed.
+//
+// For Example:
+//
+//	client.R().DownloadToFile("go.tar.gz", func(downloaded, total int64) {
+//		fmt.Printf("downloaded %d/%d bytes\n", downloaded, total)
+//	}).Get(url)
+func (r *Request) DownloadToFile(file string, progress ProgressFunc) *Request {
+	r.SetOutputFile(file)
+	r.atomicDownload = true
+	r.downloadProgress = progress
+	return r
+}
+
+// ResumeDownload is similar to DownloadToFile, but resumes the download with
+// the partial content left in the temporary file by the previous download, the
+// "Range: bytes=<size>-" header is sent, and the partial content is appended
+// to the temporary file if the server responds with 206. The ProgressFunc set
+// by DownloadToFile is kept.
+func (r *Request) ResumeDownload(file string) *Request {
+	r.DownloadToFile(file, r.downloadProgress)
+	if info, err := os.Stat(outputFilePath(r.client, file) + ".tmp"); err == nil && info.Size() > 0 {
+		r.SetHeader("Range", fmt.Sprintf("bytes=%d-", info.Size()))
+	}
+	return r
+}
+
 // SetOutput set the io.Writer that response Body will be downloaded to.
 func (r *Request) SetOutput(output io.Writer) *Request {
 	if output == nil {
@@ -553,7 +738,8 @@ func (r *Request) SetQueryParamsAnyType(params map[string]interface{}) *Request
 	return r
 }
 
-// SetQueryParam set an URL query parameter for the request.
+// SetQueryParam set an URL query parameter for the request, which overrides
+// the common query parameter with the same key set at the client level.
 func (r *Request) SetQueryParam(key, value string) *Request {
 	if r.QueryParams == nil {
 		r.QueryParams = make(urlpkg.Values)
@@ -562,7 +748,9 @@ func (r *Request) SetQueryParam(key, value string) *Request {
 	return r
 }
 
-// AddQueryParam add a URL query parameter for the request.
+// AddQueryParam add a URL query parameter for the request, the value is
+// appended to the existing values of the key, use SetQueryParam if you want
+// to replace them.
 func (r *Request) AddQueryParam(key, value string) *Request {
 	if r.QueryParams == nil {
 		r.QueryParams = make(urlpkg.Values)
@@ -582,6 +770,26 @@ func (r *Request) AddQueryParams(key string, values ...string) *Request {
 	return r
 }
 
+// RemoveQueryParam removes the URL query parameter of the request, including
+// the common query parameter set at the client level (e.g. by
+// Client.SetCommonQueryParam), which is not sent with the request.
+func (r *Request) RemoveQueryParam(key string) *Request {
+	r.QueryParams.Del(key)
+	r.removedQueryParams = append(r.removedQueryParams, key)
+	return r
+}
+
+// isQueryParamRemoved reports whether the query parameter is removed by
+// RemoveQueryParam.
+func (r *Request) isQueryParamRemoved(key string) bool {
+	for _, removed := range r.removedQueryParams {
+		if removed == key {
+			return true
+		}
+	}
+	return false
+}
+
 // SetPathParams set URL path parameters from a map for the request.
 func (r *Request) SetPathParams(params map[string]string) *Request {
 	for key, value := range params {
@@ -611,26 +819,49 @@ func (r *Request) newErrorResponse(err error) *Response {
 	return resp
 }
 
-// Do fires http request, 0 or 1 context is allowed, and returns the *Response which
-// is always not nil, and Response.Err is not nil if error occurs.
-func (r *Request) Do(ctx ...context.Context) *Response {
+// Do fires http request with the method set by SetMethod (GET if empty), 0 or
+// 1 context is allowed, and returns the *Response which is always not nil, and
+// Response.Err is not nil if error occurs. It goes through the same middleware,
+// retry, dump and error hook as Send and the method-specific calls like Get.
+func (r *Request) Do(ctx ...context.Context) (resp *Response) {
 	if len(ctx) > 0 && ctx[0] != nil {
 		r.ctx = ctx[0]
 	}
 
 	defer func() {
 		r.responseReturnTime = time.Now()
+		if resp.Err != nil && r.client.onError != nil {
+			r.client.onError(r.client, r, resp, resp.Err)
+		}
 	}()
-	if r.error != nil {
-		return r.newErrorResponse(r.error)
-	}
-	if r.retryOption != nil && r.retryOption.MaxRetries != 0 && r.unReplayableBody != nil { // retryable request should not have unreplayable Body
-		return r.newErrorResponse(errRetryableWithUnReplayableBody)
+	if r.timeout > 0 {
+		parent := r.Context()
+		ctx, cancel := context.WithTimeout(parent, r.timeout)
+		r.ctx = ctx
+		resp, _ = r.do()
+		// the timeout is derived again if the request is sent again.
+		r.ctx = parent
+		if resp.Response != nil && resp.Body != nil && resp.body == nil { // body is not read yet, cancel after body closed.
+			resp.Body = &cancelReadCloser{ReadCloser: resp.Body, cancel: cancel}
+		} else {
+			cancel()
+		}
+		return resp
 	}
-	resp, _ := r.do()
+	resp, _ = r.do()
 	return resp
 }
 
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (rc *cancelReadCloser) Close() error {
+	defer rc.cancel()
+	return rc.ReadCloser.Close()
+}
+
 func (r *Request) do() (resp *Response, err error) {
 	defer func() {
 		if resp == nil {
@@ -639,8 +870,19 @@ func (r *Request) do() (resp *Response, err error) {
 		if err != nil && resp.Err == nil {
 			resp.Err = err
 		}
+		if resp.Err != nil {
+			r.client.fireEvent(OnError, r, resp, resp.Err)
+		}
 	}()
 
+	// the errors which occur before sending also fire the OnError event.
+	if r.error != nil {
+		return r.newErrorResponse(r.error), nil
+	}
+	if r.retryOption != nil && r.retryOption.MaxRetries != 0 && r.unReplayableBody != nil { // retryable request should not have unreplayable Body
+		return r.newErrorResponse(errRetryableWithUnReplayableBody), nil
+	}
+
 	for {
 		if r.Headers == nil {
 			r.Headers = make(http.Header)
@@ -656,10 +898,10 @@ func (r *Request) do() (resp *Response, err error) {
 			}
 		}
 
-		if r.client.wrappedRoundTrip != nil {
-			resp, err = r.client.wrappedRoundTrip.RoundTrip(r)
-		} else {
-			resp, err = r.client.roundTrip(r)
+		r.client.fireEvent(BeforeRequest, r, nil, nil)
+		resp, err = r.client.roundTripper(r).RoundTrip(r)
+		if resp == nil { // middleware may short-circuit without response
+			resp = &Response{Request: r, Err: err}
 		}
 
 		// Determine if the error is from a canceled context.
@@ -671,6 +913,7 @@ func (r *Request) do() (resp *Response, err error) {
 				return
 			}
 		}
+		r.client.fireEvent(AfterResponse, r, resp, err)
 
 		if contextCanceled || r.retryOption == nil || (r.RetryAttempt >= r.retryOption.MaxRetries && r.retryOption.MaxRetries >= 0) { // absolutely cannot retry.
 			return
@@ -690,6 +933,12 @@ func (r *Request) do() (resp *Response, err error) {
 			return
 		}
 
+		// no retry if the next attempt cannot start before the context deadline.
+		interval := r.retryOption.retryInterval(resp, r.RetryAttempt+1)
+		if deadline, ok := r.Context().Deadline(); ok && time.Until(deadline) <= interval {
+			return
+		}
+
 		// need retry, attempt to retry
 		r.RetryAttempt++
 		if l := len(r.retryOption.RetryHooks); l > 0 {
@@ -697,12 +946,16 @@ func (r *Request) do() (resp *Response, err error) {
 				r.retryOption.RetryHooks[i](resp, err)
 			}
 		}
-		time.Sleep(r.retryOption.GetRetryInterval(resp, r.RetryAttempt))
+		r.client.fireEvent(OnRetry, r, resp, err)
+		if err = r.waitRetryInterval(interval); err != nil {
+			return
+		}
 
 		// clean up before retry
 		if r.dumpBuffer != nil {
 			r.dumpBuffer.Reset()
 		}
+		dumpInfo(r, fmt.Sprintf("retry attempt %d", r.RetryAttempt))
 		if r.trace != nil {
 			r.trace = &clientTrace{}
 		}
@@ -712,15 +965,28 @@ func (r *Request) do() (resp *Response, err error) {
 	}
 }
 
+// waitRetryInterval sleeps for the retry interval, returns early with the
+// context error if the request context is done while waiting.
+func (r *Request) waitRetryInterval(interval time.Duration) error {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-r.Context().Done():
+		return r.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // Send fires http request with specified method and url, returns the
 // *Response which is always not nil, and the error is not nil if error occurs.
+// The url set by SetURL is used if url is empty.
 func (r *Request) Send(method, url string) (*Response, error) {
 	r.Method = method
-	r.RawURL = url
-	resp := r.Do()
-	if resp.Err != nil && r.client.onError != nil {
-		r.client.onError(r.client, r, resp, resp.Err)
+	if url != "" {
+		r.RawURL = url
 	}
+	resp := r.Do()
 	return resp, resp.Err
 }
 
@@ -817,7 +1083,7 @@ func (r *Request) Options(url string) (*Response, error) {
 // MustHead like Head, panic if error happens, should only be used
 // to test without error handling.
 func (r *Request) MustHead(url string) *Response {
-	resp, err := r.Send(http.MethodHead, url)
+	resp, err := r.Head(url)
 	if err != nil {
 		panic(err)
 	}
@@ -830,10 +1096,20 @@ func (r *Request) Head(url string) (*Response, error) {
 }
 
 // SetBody set the request Body, accepts string, []byte, io.Reader, map and struct.
+// The encoding is inferred from the type of body:
+//  1. string and []byte are sent as is, the Content-Type is detected from the
+//     content (e.g. "text/plain; charset=utf-8", "application/octet-stream").
+//  2. io.Reader is streamed, no Content-Type is set.
+//  3. map, struct and their pointers are marshalled according to the Content-Type
+//     (XML if it contains "xml", MessagePack if it contains "msgpack"), and
+//     JSON is used by default.
+//
+// The Content-Type set explicitly at the request or client level is always kept.
 func (r *Request) SetBody(body interface{}) *Request {
 	if body == nil {
 		return r
 	}
+	r.streamBodySize = 0
 	switch b := body.(type) {
 	case io.ReadCloser:
 		r.unReplayableBody = b
@@ -865,8 +1141,73 @@ func (r *Request) SetBody(body interface{}) *Request {
 	return r
 }
 
+// SetBodyStream set the request Body as the stream body with the contentType
+// (ignored if empty) and the size, the body is sent with the `Content-Length`
+// header if size >= 0, otherwise (size is -1) the size is unknown and the body
+// is sent with chunked encoding in HTTP/1.1. The request fails if the size of
+// the body does not match the given size. Note the body is not replayable, so
+// the request can not be retried or redirected with the body.
+func (r *Request) SetBodyStream(body io.Reader, contentType string, size int64) *Request {
+	if size == 0 { // no body to send.
+		r.Body, r.GetBody, r.unReplayableBody, r.marshalBody = nil, nil, nil, nil
+		r.streamBodySize = 0
+	} else {
+		r.SetBody(body)
+		if size > 0 {
+			r.streamBodySize = size
+		}
+	}
+	if contentType != "" {
+		r.SetContentType(contentType)
+	}
+	return r
+}
+
+// SetBodyChunked set the request Body as the stream body whose length is
+// unknown, which is sent with chunked encoding and without `Content-Length` in
+// HTTP/1.1, the dump shows the chunked encoding markers as on the wire. Note
+// the body is not replayable, so the request can not be retried or redirected
+// with the body.
+func (r *Request) SetBodyChunked(body io.Reader) *Request {
+	return r.SetBodyStream(body, "", -1)
+}
+
+// GetBodyReader returns a reader over a copy of the request body without
+// consuming it, so the body can still be sent, which is useful to inspect
+// the body in the middleware or tests. The io.Reader body set by SetBody is
+// buffered in memory on the first call, which makes it replayable. The body
+// set with struct or map is not available until it is marshalled before
+// sending (e.g. in the middleware added by Client.WrapRoundTripFunc), and an
+// empty reader is returned if there is no body.
+//
+// It is not named GetBody because of the GetBody field, which it calls to
+// get a copy of the body.
+func (r *Request) GetBodyReader() (io.Reader, error) {
+	if r.Body != nil {
+		return bytes.NewReader(r.Body), nil
+	}
+	if r.GetBody == nil {
+		return bytes.NewReader(nil), nil
+	}
+	body, err := r.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if r.unReplayableBody != nil {
+		r.unReplayableBody = nil
+		r.SetBodyBytes(b)
+	}
+	return bytes.NewReader(b), nil
+}
+
 // SetBodyBytes set the request Body as []byte.
 func (r *Request) SetBodyBytes(body []byte) *Request {
+	r.streamBodySize = 0
 	r.Body = body
 	r.GetBody = func() (io.ReadCloser, error) {
 		return io.NopCloser(bytes.NewReader(body)), nil
@@ -927,6 +1268,58 @@ func (r *Request) SetBodyXmlMarshal(v interface{}) *Request {
 	return r.SetBodyXmlBytes(b)
 }
 
+// SetBodyMsgpackBytes set the request Body as []byte and set Content-Type
+// header as "application/msgpack"
+func (r *Request) SetBodyMsgpackBytes(body []byte) *Request {
+	r.SetContentType(header.MsgpackContentType)
+	return r.SetBodyBytes(body)
+}
+
+// SetBodyMsgpackMarshal set the request Body that marshaled from object, and
+// set Content-Type header as "application/msgpack", the marshal function can
+// be customized by Client.SetMsgpackMarshal.
+func (r *Request) SetBodyMsgpackMarshal(v interface{}) *Request {
+	b, err := r.client.msgpackMarshal(v)
+	if err != nil {
+		r.appendError(err)
+		return r
+	}
+	return r.SetBodyMsgpackBytes(b)
+}
+
+// SetBodyProtobuf set the request Body that marshaled from the protobuf
+// message, and set Content-Type header as "application/x-protobuf".
+func (r *Request) SetBodyProtobuf(msg proto.Message) *Request {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		r.appendError(err)
+		return r
+	}
+	r.SetContentType(header.ProtobufContentType)
+	return r.SetBodyBytes(b)
+}
+
+// SetIfNoneMatch set the `If-None-Match` header for the request, the etag is
+// quoted if it is not quoted already (e.g. "*" or the weak etag W/"xyz"), the
+// server responds with 304 if the etag matches, see Response.IsNotModified.
+// It takes precedence over the etag of the cached response if
+// Client.SetCache is used.
+func (r *Request) SetIfNoneMatch(etag string) *Request {
+	if etag != "*" && !strings.HasSuffix(etag, `"`) {
+		etag = `"` + etag + `"`
+	}
+	return r.SetHeader("If-None-Match", etag)
+}
+
+// SetIfModifiedSince set the `If-Modified-Since` header for the request in
+// the HTTP date format (RFC 1123 in GMT), the server responds with 304 if the
+// resource is not modified since t, see Response.IsNotModified. It takes
+// precedence over the Last-Modified of the cached response if
+// Client.SetCache is used.
+func (r *Request) SetIfModifiedSince(t time.Time) *Request {
+	return r.SetHeader("If-Modified-Since", t.UTC().Format(http.TimeFormat))
+}
+
 // SetContentType set the `Content-Type` for the request.
 func (r *Request) SetContentType(contentType string) *Request {
 	return r.SetHeader(header.ContentType, contentType)
@@ -955,6 +1348,15 @@ func (r *Request) SetContext(ctx context.Context) *Request {
 	return r
 }
 
+// SetTimeout set the timeout for the request, which wraps the request context
+// with a deadline before execution, including all retry attempts. It works
+// independently of the client-level timeout (Client.SetTimeout), if both are
+// set, the shorter one takes effect.
+func (r *Request) SetTimeout(d time.Duration) *Request {
+	r.timeout = d
+	return r
+}
+
 // DisableAutoReadResponse disable read response body automatically (enabled by default).
 func (r *Request) DisableAutoReadResponse() *Request {
 	r.disableAutoReadResponse = true
@@ -1087,7 +1489,11 @@ func (r *Request) EnableDumpWithoutResponseBody() *Request {
 	return r.EnableDump()
 }
 
-// EnableForceChunkedEncoding enables force using chunked encoding when uploading.
+// EnableForceChunkedEncoding enables force using chunked encoding when uploading,
+// the multipart body is streamed without buffering the whole file content in
+// memory, which is recommended for uploading large files. It does not affect
+// other request body, use SetBodyChunked to send the stream body in chunked
+// encoding.
 func (r *Request) EnableForceChunkedEncoding() *Request {
 	r.forceChunkedEncoding = true
 	return r
@@ -1153,6 +1559,22 @@ func (r *Request) SetRetryBackoffInterval(min, max time.Duration) *Request {
 	return r
 }
 
+// SetRetryExponentialInterval set retry to use an exponential backoff, the
+// interval starts from initial, grows by multiplier on every attempt, and is
+// capped at max, it's uncapped if max <= 0.
+func (r *Request) SetRetryExponentialInterval(initial, max time.Duration, multiplier float64) *Request {
+	r.getRetryOption().GetRetryInterval = exponentialInterval(initial, max, multiplier)
+	return r
+}
+
+// SetRetryMaxWait caps the interval between retry attempts, the interval
+// which exceeds max is replaced by max plus a random jitter of up to 10%,
+// zero means no cap.
+func (r *Request) SetRetryMaxWait(max time.Duration) *Request {
+	r.getRetryOption().MaxWait = max
+	return r
+}
+
 // SetRetryHook set the retry hook which will be executed before a retry.
 // It will override other retry hooks if any been added before (including
 // client-level retry hooks).
@@ -1178,13 +1600,25 @@ func (r *Request) SetRetryCondition(condition RetryConditionFunc) *Request {
 }
 
 // AddRetryCondition adds a retry condition, which determines whether the
-// request should retry.
+// request should retry. Multiple conditions are ORed, the request is retried if
+// any of them returns true.
 func (r *Request) AddRetryCondition(condition RetryConditionFunc) *Request {
 	ro := r.getRetryOption()
 	ro.RetryConditions = append(ro.RetryConditions, condition)
 	return r
 }
 
+// AddRetryOnStatus adds a retry condition which retries if the response status
+// code is one of codes (e.g. 429 and 503), and the delay in the `Retry-After`
+// header of the response is used as the retry interval if it's present (still
+// capped by SetRetryMaxWait).
+func (r *Request) AddRetryOnStatus(codes ...int) *Request {
+	ro := r.getRetryOption()
+	ro.RetryConditions = append(ro.RetryConditions, retryOnStatus(codes))
+	ro.RespectRetryAfter = true
+	return r
+}
+
 // SetClient change the client of request dynamically.
 func (r *Request) SetClient(client *Client) *Request {
 	if client != nil {
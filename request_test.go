@@ -2,13 +2,20 @@ package req
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
@@ -148,6 +155,41 @@ func TestSendMethods(t *testing.T) {
 	}
 }
 
+func TestPatch(t *testing.T) {
+	var e Echo
+	resp, err := tc().R().
+		SetBodyJsonString(`{"name":"roc"}`).
+		SetSuccessResult(&e).
+		Patch("/echo")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "PATCH", resp.GetHeader("Method"))
+	tests.AssertEqual(t, `{"name":"roc"}`, e.Body)
+	tests.AssertEqual(t, header.JsonContentType, e.Header.Get(header.ContentType))
+}
+
+func TestHead(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "11")
+		w.Write([]byte("hello world"))
+	}))
+	defer ts.Close()
+
+	resp, err := C().R().EnableDump().Head(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, http.MethodHead, resp.Request.Method)
+	// the Content-Length of the HEAD response is kept, but there is no body.
+	tests.AssertEqual(t, int64(11), resp.ContentLength)
+	tests.AssertEqual(t, "", resp.String())
+	tests.AssertContains(t, resp.Dump(), "content-length: 11", true)
+	tests.AssertContains(t, resp.Dump(), "(response to head request has no body)", true)
+	tests.AssertContains(t, resp.Dump(), "hello world", false)
+
+	resp, err = tc().R().EnableDump().Head("/")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "", resp.String())
+	tests.AssertContains(t, resp.Dump(), "(response to head request has no body)", true)
+}
+
 func testMethod(t *testing.T, c *Client, sendReq func(*Request) *Response, expectMethod string, expectPanic bool) {
 	r := c.R()
 	if expectPanic {
@@ -359,6 +401,28 @@ func TestSetBodyMarshal(t *testing.T) {
 	}
 }
 
+func TestXmlBodyDump(t *testing.T) {
+	type User struct {
+		Username string `xml:"username"`
+	}
+	var e Echo
+	resp, err := tc().R().
+		EnableDump().
+		SetBodyXmlMarshal(&User{Username: "imroc"}).
+		SetSuccessResult(&e).
+		Post("/echo")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, header.XmlContentType, e.Header.Get(header.ContentType))
+	tests.AssertContains(t, resp.Dump(), "<user><username>imroc</username></user>", true)
+
+	var user User
+	resp, err = tc().R().EnableDump().SetQueryParam("username", "imroc").SetQueryParam("type", "xml").Get("/search")
+	assertSuccess(t, resp, err)
+	tests.AssertNoError(t, resp.UnmarshalXml(&user))
+	tests.AssertEqual(t, "imroc", user.Username)
+	tests.AssertContains(t, resp.Dump(), "<username>imroc</username>", true)
+}
+
 func TestDoAPIStyle(t *testing.T) {
 	c := tc()
 	user := &UserInfo{}
@@ -389,6 +453,38 @@ func TestSetSuccessResult(t *testing.T) {
 	tests.AssertEqual(t, "imroc", resp.Result().(*UserInfo).Username)
 }
 
+func TestSetBodyStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%d %v %s %s", r.ContentLength, r.TransferEncoding, r.Header.Get(header.ContentType), body)
+	}))
+	defer ts.Close()
+
+	c := C()
+	resp, err := c.R().SetBodyStream(strings.NewReader("hello"), "text/plain", 5).Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "5 [] text/plain hello", resp.String())
+
+	// the unknown size makes the body chunked.
+	resp, err = c.R().SetBodyStream(strings.NewReader("hello"), "text/plain", -1).Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "-1 [chunked] text/plain hello", resp.String())
+
+	resp, err = c.R().SetBodyStream(strings.NewReader(""), "", 0).Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "0 []  ", resp.String())
+
+	// the size must match the body.
+	_, err = c.R().SetBodyStream(strings.NewReader("hello"), "text/plain", 10).Post(ts.URL)
+	tests.AssertErrorContains(t, err, "ContentLength=10 with Body length")
+
+	// the size is reset if the body is replaced.
+	resp, err = c.R().SetBodyStream(strings.NewReader("hello"), "text/plain", 10).
+		SetBody(strings.NewReader("hi")).Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "-1 [chunked] text/plain hi", resp.String())
+}
+
 func TestSetBody(t *testing.T) {
 	body := "hello"
 	fn := func() (io.ReadCloser, error) {
@@ -477,6 +573,75 @@ func TestSetBody(t *testing.T) {
 		tests.AssertEqual(t, tc.ContentType, e.Header.Get(header.ContentType))
 		tests.AssertEqual(t, body, e.Body)
 	}
+
+	// the content type of the binary body is detected.
+	var e Echo
+	resp, err := c.R().SetBody([]byte{0x00, 0x01, 0x02}).SetSuccessResult(&e).Post("/echo")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "application/octet-stream", e.Header.Get(header.ContentType))
+
+	// the explicit content type is kept.
+	e = Echo{}
+	resp, err = c.R().SetContentType("application/custom").SetBody(body).SetSuccessResult(&e).Post("/echo")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "application/custom", e.Header.Get(header.ContentType))
+	e = Echo{}
+	resp, err = c.R().SetBody(map[string]string{"name": "roc"}).SetSuccessResult(&e).Post("/echo")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, header.JsonContentType, e.Header.Get(header.ContentType))
+	tests.AssertEqual(t, `{"name":"roc"}`, e.Body)
+}
+
+func TestGetBodyReader(t *testing.T) {
+	body := "hello"
+	for _, setBody := range []func(r *Request){
+		func(r *Request) { r.SetBody(bytes.NewBufferString(body)) },
+		func(r *Request) { r.SetBody(io.NopCloser(bytes.NewBufferString(body))) },
+		func(r *Request) {
+			r.SetBody(func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewBufferString(body)), nil
+			})
+		},
+		func(r *Request) { r.SetBodyString(body) },
+	} {
+		r := tc().R().SetRetryCount(1)
+		setBody(r)
+		for i := 0; i < 2; i++ {
+			reader, err := r.GetBodyReader()
+			tests.AssertNoError(t, err)
+			b, err := io.ReadAll(reader)
+			tests.AssertNoError(t, err)
+			tests.AssertEqual(t, body, string(b))
+		}
+		var e Echo
+		resp, err := r.SetSuccessResult(&e).Post("/echo")
+		assertSuccess(t, resp, err)
+		tests.AssertEqual(t, body, e.Body)
+	}
+
+	reader, err := tc().R().GetBodyReader()
+	tests.AssertNoError(t, err)
+	b, _ := io.ReadAll(reader)
+	tests.AssertEqual(t, 0, len(b))
+
+	// the marshalled body is available in the round trip middleware.
+	var peeked string
+	c := tc().WrapRoundTripFunc(func(rt RoundTripper) RoundTripFunc {
+		return func(req *Request) (*Response, error) {
+			reader, err := req.GetBodyReader()
+			if err != nil {
+				return nil, err
+			}
+			b, _ := io.ReadAll(reader)
+			peeked = string(b)
+			return rt.RoundTrip(req)
+		}
+	})
+	var e Echo
+	resp, err := c.R().SetBody(map[string]string{"name": "roc"}).SetSuccessResult(&e).Post("/echo")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, `{"name":"roc"}`, peeked)
+	tests.AssertEqual(t, peeked, e.Body)
 }
 
 func TestCookie(t *testing.T) {
@@ -493,6 +658,16 @@ func TestCookie(t *testing.T) {
 	).SetSuccessResult(&headers).Get("/header")
 	assertSuccess(t, resp, err)
 	tests.AssertEqual(t, "cookie1=value1; cookie2=value2", headers.Get("Cookie"))
+
+	// the request cookies bypass the cookie jar.
+	c := tc().SetCookieJar(nil)
+	resp, err = c.R().SetCookies(&http.Cookie{Name: "token", Value: "abc"}).SetSuccessResult(&headers).Get("/header")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "token=abc", headers.Get("Cookie"))
+	headers = make(http.Header)
+	resp, err = c.R().SetSuccessResult(&headers).Get("/header")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "", headers.Get("Cookie"))
 }
 
 func TestSetBasicAuth(t *testing.T) {
@@ -505,6 +680,22 @@ func TestSetBasicAuth(t *testing.T) {
 	tests.AssertEqual(t, "Basic aW1yb2M6MTIzNDU2", headers.Get("Authorization"))
 }
 
+func TestSetBasicAuthFromEnv(t *testing.T) {
+	t.Setenv("REQ_TEST_USER", "imroc")
+	t.Setenv("REQ_TEST_PASS", "123456")
+	headers := make(http.Header)
+	resp, err := tc().R().
+		SetBasicAuthFromEnv("REQ_TEST_USER", "REQ_TEST_PASS").
+		SetSuccessResult(&headers).
+		Get("/header")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "Basic aW1yb2M6MTIzNDU2", headers.Get("Authorization"))
+
+	buf := new(bytes.Buffer)
+	tc().SetLogger(NewLogger(buf, "", 0)).R().SetBasicAuthFromEnv("REQ_TEST_USER", "REQ_TEST_MISSING")
+	tests.AssertContains(t, buf.String(), "req_test_missing for basic auth password is empty", true)
+}
+
 func TestSetBearerAuthToken(t *testing.T) {
 	token := "NGU1ZWYwZDJhNmZhZmJhODhmMjQ3ZDc4"
 	headers := make(http.Header)
@@ -516,6 +707,38 @@ func TestSetBearerAuthToken(t *testing.T) {
 	tests.AssertEqual(t, "Bearer "+token, headers.Get("Authorization"))
 }
 
+func TestTraceID(t *testing.T) {
+	l := &structuredLogger{Logger: NewLogger(new(bytes.Buffer), "", 0)}
+	c := tc().SetLogger(l)
+	resp, err := c.R().EnableDumpWithoutBody().SetTraceID("my-trace-id").Get("/")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "my-trace-id", l.reqHeader.Get("X-Request-ID"))
+	tests.AssertContains(t, resp.Dump(), "x-request-id: my-trace-id", true)
+
+	var ids []string
+	r := c.SetTraceIDHeader("X-Trace-ID").R().
+		EnableAutoTraceID().
+		SetRetryCount(2).
+		SetRetryFixedInterval(time.Millisecond).
+		AddRetryOnStatus(http.StatusTooManyRequests).
+		AddRetryHook(func(resp *Response, err error) {
+			ids = append(ids, resp.Request.RawRequest.Header.Get("X-Trace-ID"))
+		})
+	tests.AssertEqual(t, "", r.GetTraceID())
+	resp, err = r.Get("/too-many")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, true, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(r.GetTraceID()))
+	tests.AssertEqual(t, r.GetTraceID(), l.reqHeader.Get("X-Trace-ID"))
+	tests.AssertEqual(t, "", l.reqHeader.Get("X-Request-ID"))
+	// the same trace ID is sent in all retry attempts.
+	tests.AssertEqual(t, []string{r.GetTraceID(), r.GetTraceID()}, ids)
+
+	// the auto trace ID is not generated if it's set explicitly.
+	resp, err = c.R().EnableAutoTraceID().SetTraceID("fixed").Get("/")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "fixed", l.reqHeader.Get("X-Trace-ID"))
+}
+
 func TestHeader(t *testing.T) {
 	testWithAllTransport(t, testHeader)
 }
@@ -540,6 +763,32 @@ func testHeader(t *testing.T, c *Client) {
 	tests.AssertEqual(t, "value1", headers.Get("header1"))
 	tests.AssertEqual(t, "value2", headers.Get("header2"))
 	tests.AssertEqual(t, "value3", headers.Get("header3"))
+
+	// merge with and override the common headers.
+	c.SetCommonHeaders(map[string]string{
+		"header1": "common1",
+		"header2": "common2",
+		"header3": "common3",
+	}).SetCommonHeaderNonCanonical("common-Header", "common")
+	headers = make(http.Header)
+	resp, err = c.R().
+		SetHeader("header1", "value1").
+		DelHeader("header2").
+		DelHeader("Common-Header").
+		SetSuccessResult(&headers).
+		Get("/header")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "value1", headers.Get("header1"))
+	tests.AssertEqual(t, "", headers.Get("header2"))
+	tests.AssertEqual(t, "common3", headers.Get("header3"))
+	tests.AssertEqual(t, "", headers.Get("common-Header"))
+
+	// the deleted header can be set again.
+	headers = make(http.Header)
+	resp, err = c.R().DelHeader("header2").SetHeader("header2", "value2").
+		SetSuccessResult(&headers).Get("/header")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "value2", headers.Get("header2"))
 }
 
 func TestSetHeaderNonCanonical(t *testing.T) {
@@ -652,6 +901,27 @@ func testPathParam(t *testing.T, c *Client) {
 	tests.AssertEqual(t, fmt.Sprintf("%s's profile", username), resp.String())
 }
 
+func TestURLBuilder(t *testing.T) {
+	resp, err := tc().R().
+		SetURL("/user/{username}/profile?type=json").
+		SetPathParam("username", "roc/imroc").
+		AddQueryParams("key", "a&b", "中文").
+		AddQueryParam("key", "c").
+		Get("")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "/user/roc%2Fimroc/profile", resp.Request.URL.EscapedPath())
+	tests.AssertEqual(t, "type=json&key=a%26b&key=%E4%B8%AD%E6%96%87&key=c", resp.Request.URL.RawQuery)
+
+	resp, err = tc().R().
+		SetURL("/query-parameter").
+		AddQueryParam("key", "a").
+		AddQueryParam("key", "b").
+		SetQueryParam("key", "c").
+		Get("")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "key=c", resp.String())
+}
+
 func TestSuccess(t *testing.T) {
 	testWithAllTransport(t, testSuccess)
 }
@@ -715,6 +985,57 @@ func testError(t *testing.T, c *Client) {
 	tests.AssertEqual(t, 10000, em.ErrorCode)
 }
 
+func TestSetResultAndSetError(t *testing.T) {
+	for _, typ := range []string{"json", "xml"} {
+		var userInfo UserInfo
+		var errMsg ErrorMessage
+		resp, err := tc().R().
+			SetQueryParam("username", "imroc").
+			SetQueryParam("type", typ).
+			SetResult(&userInfo).
+			SetError(&errMsg).
+			Get("/search")
+		assertSuccess(t, resp, err)
+		tests.AssertEqual(t, "roc@imroc.cc", userInfo.Email)
+		tests.AssertEqual(t, &userInfo, resp.Result())
+		tests.AssertIsNil(t, resp.Error())
+
+		userInfo = UserInfo{}
+		resp, err = tc().R().
+			SetQueryParam("username", "test").
+			SetQueryParam("type", typ).
+			SetResult(&userInfo).
+			SetError(&errMsg).
+			Get("/search")
+		assertIsError(t, resp, err)
+		tests.AssertEqual(t, 10001, errMsg.ErrorCode)
+		tests.AssertEqual(t, &errMsg, resp.Error())
+		tests.AssertIsNil(t, resp.Result())
+	}
+}
+
+func TestToBytesCached(t *testing.T) {
+	resp, err := tc().R().
+		SetQueryParam("username", "imroc").
+		DisableAutoReadResponse().
+		Get("/search")
+	assertSuccess(t, resp, err)
+	tests.AssertIsNil(t, resp.Bytes())
+
+	b, err := resp.ToBytes()
+	tests.AssertNoError(t, err)
+	tests.AssertContains(t, string(b), "roc@imroc.cc", true)
+	// the body is exhausted and closed, the cached body is returned.
+	s, err := resp.ToString()
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, string(b), s)
+	tests.AssertEqual(t, b, resp.Bytes())
+
+	var userInfo UserInfo
+	tests.AssertNoError(t, resp.UnmarshalJson(&userInfo))
+	tests.AssertEqual(t, "roc@imroc.cc", userInfo.Email)
+}
+
 func TestForm(t *testing.T) {
 	testWithAllTransport(t, testForm)
 }
@@ -740,6 +1061,17 @@ func testForm(t *testing.T, c *Client) {
 		Post("/search")
 	assertSuccess(t, resp, err)
 	tests.AssertEqual(t, "roc@imroc.cc", userInfo.Email)
+
+	resp, err = c.R().
+		AddFormData("username", "imroc").
+		AddFormData("type", "xml").
+		SetSuccessResult(&userInfo).
+		Post("/search")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "roc@imroc.cc", userInfo.Email)
+
+	_, err = c.R().AddFormData("username", "imroc").SetBodyJsonMarshal(v).Post("/search")
+	tests.AssertEqual(t, errFormDataWithBody, err)
 }
 
 func TestHostHeaderOverride(t *testing.T) {
@@ -808,6 +1140,15 @@ func testTraceInfo(t *testing.T, c *Client) {
 	resp, err = c.R().EnableTrace().Get("/")
 	assertSuccess(t, resp, err)
 	assertEnableTraceInfo(t, resp)
+
+	// the connection is reused by the next request.
+	resp, err = c.R().EnableTrace().Get("/")
+	assertSuccess(t, resp, err)
+	assertEnableTraceInfo(t, resp)
+	ti := resp.TraceInfo()
+	tests.AssertEqual(t, true, ti.IsConnReused)
+	tests.AssertEqual(t, time.Duration(0), ti.DNSLookupTime)
+	tests.AssertContains(t, ti.String(), "isconnreused:     : true", true)
 }
 
 func TestTraceOnTimeout(t *testing.T) {
@@ -830,6 +1171,45 @@ func TestTraceOnTimeout(t *testing.T) {
 	})
 }
 
+func TestRequestTimeout(t *testing.T) {
+	testWithAllTransport(t, func(t *testing.T, c *Client) {
+		_, err := c.R().SetTimeout(50 * time.Millisecond).Get("/sleep?d=1s")
+		tests.AssertEqual(t, true, errors.Is(err, context.DeadlineExceeded))
+
+		// the shorter one wins if both client and request timeout are set.
+		assertShorterTimeout := func(r *Request) {
+			start := time.Now()
+			_, err := r.Get("/sleep?d=1s")
+			var netErr net.Error
+			tests.AssertEqual(t, true, errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()))
+			tests.AssertEqual(t, true, time.Since(start) < 500*time.Millisecond)
+		}
+		assertShorterTimeout(c.SetTimeout(50 * time.Millisecond).R().SetTimeout(time.Minute))
+		assertShorterTimeout(c.SetTimeout(time.Minute).R().SetTimeout(50 * time.Millisecond))
+
+		resp, err := c.SetTimeout(time.Minute).R().
+			SetTimeout(time.Second).
+			DisableAutoReadResponse().
+			Get("/sleep?d=10ms")
+		assertSuccess(t, resp, err)
+		result, err := resp.ToString()
+		tests.AssertNoError(t, err)
+		tests.AssertEqual(t, "wake up", result)
+
+		// the timeout is derived from the request context.
+		ctx, cancel := context.WithCancel(context.Background())
+		r := c.R().SetContext(ctx).SetTimeout(time.Minute)
+		resp, err = r.Get("/")
+		assertSuccess(t, resp, err)
+		resp, err = r.Get("/") // sent again after the previous timeout context is canceled.
+		assertSuccess(t, resp, err)
+		tests.AssertEqual(t, ctx, r.Context())
+		cancel()
+		_, err = r.Get("/")
+		tests.AssertEqual(t, true, errors.Is(err, context.Canceled))
+	})
+}
+
 func TestAutoDetectRequestContentType(t *testing.T) {
 	c := tc()
 	resp, err := c.R().SetBody(getTestFileContent(t, "sample-image.png")).Post("/content-type")
@@ -923,6 +1303,71 @@ func TestSetFileWithRetry(t *testing.T) {
 	tests.AssertEqual(t, 2, resp.Request.RetryAttempt)
 }
 
+func TestSetFileStreamWithRetry(t *testing.T) {
+	resp, err := tc().R().
+		EnableForceChunkedEncoding().
+		SetRetryCount(3).
+		SetRetryCondition(func(resp *Response, err error) bool {
+			return err != nil || resp.StatusCode > 499
+		}).
+		SetRetryHook(func(resp *Response, err error) {
+			resp.Request.SetQueryParam("attempt", strconv.Itoa(resp.Request.RetryAttempt))
+		}).
+		SetFile("file", tests.GetTestFilePath("sample-file.txt")).
+		SetQueryParam("attempt", "0").
+		Post("/file-text")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, 2, resp.Request.RetryAttempt)
+	tests.AssertEqual(t, getTestFileContent(t, "sample-file.txt"), resp.Bytes())
+}
+
+func TestForceChunkedEncoding(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %d %s", strings.Join(r.TransferEncoding, ","), r.ContentLength, b)
+	}))
+	defer ts.Close()
+
+	resp, err := C().R().SetBody("hello").Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, " 5 hello", resp.String())
+
+	// EnableForceChunkedEncoding only affects the multipart body.
+	resp, err = C().R().SetBody("hello").EnableForceChunkedEncoding().Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, " 5 hello", resp.String())
+	resp, err = C().R().SetFileReader("file", "hello.txt", strings.NewReader("hello")).EnableForceChunkedEncoding().Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, true, strings.HasPrefix(resp.String(), "chunked -1 "))
+	resp, err = C().R().SetFileReader("file", "hello.txt", strings.NewReader("hello")).Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, false, strings.HasPrefix(resp.String(), "chunked"))
+
+	resp, err = C().R().SetBodyChunked(bytes.NewReader([]byte("hello"))).EnableDumpWithoutResponse().Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "chunked -1 hello", resp.String())
+	// the dump shows the chunked encoding markers.
+	tests.AssertContains(t, resp.Dump(), "transfer-encoding: chunked\r\n", true)
+	tests.AssertContains(t, resp.Dump(), "\r\n\r\n5\r\nhello\r\n0\r\n\r\n", true)
+
+	// the io.Reader body is always chunked.
+	resp, err = C().R().SetBody(strings.NewReader("hello")).Post(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "chunked -1 hello", resp.String())
+}
+
+func TestSetFileUploadError(t *testing.T) {
+	errRead := errors.New("read file failed")
+	_, err := tc().R().SetFileUpload(FileUpload{
+		ParamName: "file",
+		FileName:  "file.txt",
+		GetFileContent: func() (io.ReadCloser, error) {
+			return nil, errRead
+		},
+	}).Post("/file-text")
+	tests.AssertEqual(t, errRead, err)
+}
+
 func TestSetFile(t *testing.T) {
 	filename := "sample-file.txt"
 	resp := uploadTextFile(t, func(r *Request) {
@@ -934,6 +1379,45 @@ func TestSetFile(t *testing.T) {
 	tests.AssertErrorContains(t, err, "no such file")
 }
 
+func TestSetFormFile(t *testing.T) {
+	filename := "sample-file.txt"
+	file, err := os.Open(tests.GetTestFilePath(filename))
+	tests.AssertNoError(t, err)
+	r := tc().R().
+		SetRetryCount(3).
+		SetRetryCondition(func(resp *Response, err error) bool {
+			return err != nil || resp.StatusCode > 499
+		}).
+		SetRetryHook(func(resp *Response, err error) {
+			resp.Request.SetQueryParam("attempt", strconv.Itoa(resp.Request.RetryAttempt))
+		}).
+		SetFormFile("file", file).
+		SetQueryParam("attempt", "0")
+	tests.AssertEqual(t, filename, r.uploadFiles[0].FileName)
+	fileInfo, err := os.Stat(tests.GetTestFilePath(filename))
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, fileInfo.Size(), r.uploadFiles[0].FileSize)
+	resp, err := r.Post("/file-text")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, 2, resp.Request.RetryAttempt)
+	tests.AssertEqual(t, getTestFileContent(t, filename), resp.Bytes())
+
+	// the Content-Length of the part is set to the file size.
+	file, err = os.Open(tests.GetTestFilePath(filename))
+	tests.AssertNoError(t, err)
+	var result struct {
+		Files map[string][]*multipart.FileHeader `json:"files"`
+	}
+	resp, err = tc().R().SetFormFile("file", file).SetSuccessResult(&result).Post("/multipart")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, 1, len(result.Files["file"]))
+	tests.AssertEqual(t, strconv.FormatInt(fileInfo.Size(), 10), result.Files["file"][0].Header.Get("Content-Length"))
+
+	// the file is closed after upload.
+	_, err = tc().SetLogger(nil).R().SetFormFile("file", file).Post("/file-text")
+	tests.AssertErrorContains(t, err, "file already closed")
+}
+
 func TestSetFiles(t *testing.T) {
 	filename := "sample-file.txt"
 	resp := uploadTextFile(t, func(r *Request) {
@@ -997,6 +1481,270 @@ func TestDownloadCallback(t *testing.T) {
 	tests.AssertEqual(t, true, n > 0)
 }
 
+func TestDownloadToFile(t *testing.T) {
+	content := strings.Repeat("0123456789", 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/notfound" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "file.txt", time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	c := C().SetBaseURL(server.URL).SetOutputDirectory(dir)
+	var downloaded, total int64
+	progress := func(d, t int64) {
+		downloaded, total = d, t
+	}
+	resp, err := c.R().DownloadToFile("file.txt", progress).Get("/")
+	assertSuccess(t, resp, err)
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, content, string(data))
+	tests.AssertEqual(t, int64(len(content)), downloaded)
+	tests.AssertEqual(t, int64(len(content)), total)
+	_, err = os.Stat(filepath.Join(dir, "file.txt.tmp"))
+	tests.AssertEqual(t, true, os.IsNotExist(err))
+
+	// resume from the partial content
+	err = os.WriteFile(filepath.Join(dir, "resume.txt.tmp"), []byte(content[:300]), 0644)
+	tests.AssertNoError(t, err)
+	resp, err = c.R().DownloadToFile("resume.txt", progress).ResumeDownload("resume.txt").Get("/")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, http.StatusPartialContent, resp.StatusCode)
+	data, err = os.ReadFile(filepath.Join(dir, "resume.txt"))
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, content, string(data))
+	tests.AssertEqual(t, int64(len(content)), downloaded)
+	tests.AssertEqual(t, int64(len(content)), total)
+
+	// the file is not written if the response is not successful
+	resp, err = c.R().DownloadToFile("notfound.txt", nil).Get("/notfound")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, http.StatusNotFound, resp.StatusCode)
+	_, err = os.Stat(filepath.Join(dir, "notfound.txt"))
+	tests.AssertEqual(t, true, os.IsNotExist(err))
+}
+
+func TestResponseSaveBody(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sub", "body.txt")
+	resp, err := tc().R().Get("/")
+	assertSuccess(t, resp, err)
+	tests.AssertNoError(t, resp.SaveBody(file))
+	tests.AssertNoError(t, resp.SaveBody(file)) // the read body can be saved again
+	data, err := os.ReadFile(file)
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, "TestGet: text response", string(data))
+	entries, _ := os.ReadDir(filepath.Dir(file))
+	tests.AssertEqual(t, 1, len(entries)) // no temporary file left
+
+	resp, err = tc().R().DisableAutoReadResponse().Get("/")
+	assertSuccess(t, resp, err)
+	tests.AssertNoError(t, resp.SaveBodyAppend(file))
+	tests.AssertEqual(t, ErrBodyAlreadyRead, resp.SaveBodyAppend(file))
+	data, err = os.ReadFile(file)
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, "TestGet: text responseTestGet: text response", string(data))
+}
+
+func TestSetMethodDo(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Method))
+	}))
+	defer ts.Close()
+	var hookErr error
+	c := C().OnError(func(client *Client, req *Request, resp *Response, err error) {
+		hookErr = err
+	})
+	for _, method := range []string{http.MethodPatch, http.MethodDelete, "PURGE"} {
+		resp := c.R().SetMethod(method).SetURL(ts.URL).Do()
+		assertSuccess(t, resp, resp.Err)
+		tests.AssertEqual(t, method, resp.String())
+	}
+	resp := c.R().SetURL(ts.URL).Do()
+	assertSuccess(t, resp, resp.Err)
+	tests.AssertEqual(t, http.MethodGet, resp.String())
+
+	resp = c.R().SetMethod(http.MethodPatch).SetURL("http://%xx").Do()
+	tests.AssertNotNil(t, resp.Err)
+	tests.AssertEqual(t, resp.Err, hookErr)
+}
+
+func TestResponseStatusHelpers(t *testing.T) {
+	c := tc()
+	for _, tc := range []struct {
+		Code                                                    int
+		Success, Redirect, ClientError, ServerError, ErrorState bool
+	}{
+		{Code: 200, Success: true},
+		{Code: 304, Redirect: true},
+		{Code: 404, ClientError: true, ErrorState: true},
+		{Code: 503, ServerError: true, ErrorState: true},
+	} {
+		resp, err := c.R().SetQueryParam("code", strconv.Itoa(tc.Code)).Get("/status")
+		tests.AssertNoError(t, err)
+		tests.AssertEqual(t, tc.Success, resp.IsSuccessState())
+		tests.AssertEqual(t, tc.Redirect, resp.IsRedirect())
+		tests.AssertEqual(t, tc.ClientError, resp.IsClientError())
+		tests.AssertEqual(t, tc.ServerError, resp.IsServerError())
+		tests.AssertEqual(t, tc.ErrorState, resp.IsErrorState())
+	}
+	resp := &Response{}
+	tests.AssertEqual(t, false, resp.IsRedirect() || resp.IsClientError() || resp.IsServerError())
+}
+
+func TestResponseContentHelpers(t *testing.T) {
+	resp, err := tc().R().Get("/gbk")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "text/plain", resp.GetMediaType())
+	tests.AssertEqual(t, "gbk", resp.GetCharset())
+	tests.AssertEqual(t, int64(len(toGbk("我是roc"))), resp.GetContentLength())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(header.ContentType, "Application/JSON")
+		w.Write([]byte("{"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("}"))
+	}))
+	defer ts.Close()
+	resp, err = C().R().Get(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "application/json", resp.GetMediaType())
+	tests.AssertEqual(t, "", resp.GetCharset())
+	tests.AssertEqual(t, int64(-1), resp.GetContentLength())
+
+	resp = &Response{}
+	tests.AssertEqual(t, "", resp.GetMediaType())
+	tests.AssertEqual(t, int64(-1), resp.GetContentLength())
+}
+
+func TestResponseHeaderMap(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Multi", "a")
+		w.Header().Add("X-Multi", "b")
+		w.Header()["x-non-canonical"] = []string{"v"}
+	}))
+	defer ts.Close()
+	resp, err := C().R().Get(ts.URL)
+	assertSuccess(t, resp, err)
+	m := resp.HeaderMap()
+	tests.AssertEqual(t, "a", m["x-multi"])
+	tests.AssertEqual(t, "v", m["x-non-canonical"])
+	tests.AssertEqual(t, "0", m["content-length"])
+	tests.AssertEqual(t, 0, len((&Response{}).HeaderMap()))
+}
+
+func TestResponseRedirectURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/relative":
+			w.Header().Set(header.Location, "../target?a=b")
+			w.WriteHeader(http.StatusFound)
+		case "/absolute":
+			w.Header().Set(header.Location, "https://example.com/target")
+			w.WriteHeader(http.StatusMovedPermanently)
+		case "/no-location":
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set(header.Location, "/target")
+		}
+	}))
+	defer ts.Close()
+
+	c := C().SetBaseURL(ts.URL).DisableAutoRedirect()
+	resp, err := c.R().Get("/relative")
+	tests.AssertNoError(t, err)
+	u, ok := resp.RedirectURL()
+	tests.AssertEqual(t, true, ok)
+	tests.AssertEqual(t, ts.URL+"/target?a=b", u.String())
+
+	resp, err = c.R().Get("/absolute")
+	tests.AssertNoError(t, err)
+	u, ok = resp.RedirectURL()
+	tests.AssertEqual(t, true, ok)
+	tests.AssertEqual(t, "https://example.com/target", u.String())
+
+	for _, path := range []string{"/no-location", "/not-redirect"} {
+		resp, err = c.R().Get(path)
+		tests.AssertNoError(t, err)
+		u, ok = resp.RedirectURL()
+		tests.AssertEqual(t, false, ok)
+		tests.AssertIsNil(t, u)
+	}
+
+	_, ok = (&Response{}).RedirectURL()
+	tests.AssertEqual(t, false, ok)
+}
+
+func TestResponseCookie(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark"})
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "def"})
+	}))
+	defer ts.Close()
+	resp, err := C().SetCookieJar(nil).R().Get(ts.URL)
+	assertSuccess(t, resp, err)
+	cookie, err := resp.Cookie("session")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, "abc", cookie.Value)
+	tests.AssertEqual(t, "/", cookie.Path)
+	_, err = resp.Cookie("missing")
+	tests.AssertEqual(t, http.ErrNoCookie, err)
+
+	m := resp.CookieMap()
+	tests.AssertEqual(t, 2, len(m))
+	tests.AssertEqual(t, "abc", m["session"].Value)
+	tests.AssertEqual(t, "dark", m["theme"].Value)
+
+	_, err = (&Response{}).Cookie("session")
+	tests.AssertEqual(t, http.ErrNoCookie, err)
+	tests.AssertEqual(t, 0, len((&Response{}).CookieMap()))
+}
+
+func TestExpectStatus(t *testing.T) {
+	c := tc()
+	resp, err := c.R().ExpectStatusOK().Get("/")
+	assertSuccess(t, resp, err)
+
+	_, err = c.R().ExpectStatus(http.StatusOK, http.StatusBadRequest).Get("/bad-request")
+	tests.AssertNoError(t, err)
+
+	_, err = c.R().ExpectStatusOK().Get("/bad-request")
+	var mismatch *StatusMismatchError
+	tests.AssertEqual(t, true, errors.As(err, &mismatch))
+	tests.AssertEqual(t, []int{http.StatusOK}, mismatch.Expected)
+	tests.AssertEqual(t, http.StatusBadRequest, mismatch.Actual)
+	tests.AssertEqual(t, "unexpected status code 400, expected [200]", err.Error())
+}
+
+func TestResponseLinks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", `<https://api.example.com/items?page=3&a=1,2>; rel="next", </items?page=1>; rel="prev first"`)
+		w.Header().Add("Link", `<https://api.example.com/items?page=9>; title="last, page"; rel=last`)
+	}))
+	defer ts.Close()
+	resp, err := C().R().Get(ts.URL + "/items?page=2")
+	assertSuccess(t, resp, err)
+	links := resp.Links()
+	tests.AssertEqual(t, 4, len(links))
+	tests.AssertEqual(t, "https://api.example.com/items?page=3&a=1,2", links["next"].String())
+	tests.AssertEqual(t, ts.URL+"/items?page=1", links["prev"].String())
+	tests.AssertEqual(t, ts.URL+"/items?page=1", links["first"].String())
+	tests.AssertEqual(t, "https://api.example.com/items?page=9", links["last"].String())
+	next, ok := resp.NextPageURL()
+	tests.AssertEqual(t, true, ok)
+	tests.AssertEqual(t, links["next"], next)
+
+	resp, err = tc().R().Get("/")
+	assertSuccess(t, resp, err)
+	_, ok = resp.NextPageURL()
+	tests.AssertEqual(t, false, ok)
+}
+
 func TestRequestDisableAutoReadResponse(t *testing.T) {
 	testWithAllTransport(t, func(t *testing.T, c *Client) {
 		resp, err := c.R().DisableAutoReadResponse().Get("/")
@@ -5,9 +5,30 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"time"
+
+	"google.golang.org/protobuf/proto"
 )
 
+// SetMethod is a global wrapper methods which delegated
+// to the default client, create a request and SetMethod for request.
+func SetMethod(method string) *Request {
+	return defaultClient.R().SetMethod(method)
+}
+
+// ExpectStatus is a global wrapper methods which delegated
+// to the default client, create a request and ExpectStatus for request.
+func ExpectStatus(codes ...int) *Request {
+	return defaultClient.R().ExpectStatus(codes...)
+}
+
+// ExpectStatusOK is a global wrapper methods which delegated
+// to the default client, create a request and ExpectStatusOK for request.
+func ExpectStatusOK() *Request {
+	return defaultClient.R().ExpectStatusOK()
+}
+
 // SetURL is a global wrapper methods which delegated
 // to the default client, create a request and SetURL for request.
 func SetURL(url string) *Request {
@@ -20,6 +41,24 @@ func SetFormDataFromValues(data url.Values) *Request {
 	return defaultClient.R().SetFormDataFromValues(data)
 }
 
+// AddFormData is a global wrapper methods which delegated
+// to the default client, create a request and AddFormData for request.
+func AddFormData(key, value string) *Request {
+	return defaultClient.R().AddFormData(key, value)
+}
+
+// SetGraphQL is a global wrapper methods which delegated
+// to the default client, create a request and SetGraphQL for request.
+func SetGraphQL(query string, variables map[string]interface{}) *Request {
+	return defaultClient.R().SetGraphQL(query, variables)
+}
+
+// SetGraphQLFromFile is a global wrapper methods which delegated
+// to the default client, create a request and SetGraphQLFromFile for request.
+func SetGraphQLFromFile(file string, variables map[string]interface{}) *Request {
+	return defaultClient.R().SetGraphQLFromFile(file, variables)
+}
+
 // SetFormData is a global wrapper methods which delegated
 // to the default client, create a request and SetFormData for request.
 func SetFormData(data map[string]string) *Request {
@@ -68,6 +107,12 @@ func SetFile(paramName, filePath string) *Request {
 	return defaultClient.R().SetFile(paramName, filePath)
 }
 
+// SetFormFile is a global wrapper methods which delegated
+// to the default client, create a request and SetFormFile for request.
+func SetFormFile(paramName string, file *os.File) *Request {
+	return defaultClient.R().SetFormFile(paramName, file)
+}
+
 // SetFileUpload is a global wrapper methods which delegated
 // to the default client, create a request and SetFileUpload for request.
 func SetFileUpload(f ...FileUpload) *Request {
@@ -114,6 +159,12 @@ func SetBasicAuth(username, password string) *Request {
 	return defaultClient.R().SetBasicAuth(username, password)
 }
 
+// SetBasicAuthFromEnv is a global wrapper methods which delegated
+// to the default client, create a request and SetBasicAuthFromEnv for request.
+func SetBasicAuthFromEnv(userEnv, passEnv string) *Request {
+	return defaultClient.R().SetBasicAuthFromEnv(userEnv, passEnv)
+}
+
 // SetDigestAuth is a global wrapper methods which delegated
 // to the default client, create a request and SetDigestAuth for request.
 func SetDigestAuth(username, password string) *Request {
@@ -132,6 +183,24 @@ func SetHeader(key, value string) *Request {
 	return defaultClient.R().SetHeader(key, value)
 }
 
+// DelHeader is a global wrapper methods which delegated
+// to the default client, create a request and DelHeader for request.
+func DelHeader(key string) *Request {
+	return defaultClient.R().DelHeader(key)
+}
+
+// SetTraceID is a global wrapper methods which delegated
+// to the default client, create a request and SetTraceID for request.
+func SetTraceID(id string) *Request {
+	return defaultClient.R().SetTraceID(id)
+}
+
+// EnableAutoTraceID is a global wrapper methods which delegated
+// to the default client, create a request and EnableAutoTraceID for request.
+func EnableAutoTraceID() *Request {
+	return defaultClient.R().EnableAutoTraceID()
+}
+
 // SetHeaderOrder is a global wrapper methods which delegated
 // to the default client, create a request and SetHeaderOrder for request.
 func SetHeaderOrder(keys ...string) *Request {
@@ -150,6 +219,18 @@ func SetOutputFile(file string) *Request {
 	return defaultClient.R().SetOutputFile(file)
 }
 
+// DownloadToFile is a global wrapper methods which delegated
+// to the default client, create a request and DownloadToFile for request.
+func DownloadToFile(file string, progress ProgressFunc) *Request {
+	return defaultClient.R().DownloadToFile(file, progress)
+}
+
+// ResumeDownload is a global wrapper methods which delegated
+// to the default client, create a request and ResumeDownload for request.
+func ResumeDownload(file string) *Request {
+	return defaultClient.R().ResumeDownload(file)
+}
+
 // SetOutput is a global wrapper methods which delegated
 // to the default client, create a request and SetOutput for request.
 func SetOutput(output io.Writer) *Request {
@@ -186,6 +267,12 @@ func AddQueryParams(key string, values ...string) *Request {
 	return defaultClient.R().AddQueryParams(key, values...)
 }
 
+// RemoveQueryParam is a global wrapper methods which delegated
+// to the default client, create a request and RemoveQueryParam for request.
+func RemoveQueryParam(key string) *Request {
+	return defaultClient.R().RemoveQueryParam(key)
+}
+
 // SetPathParams is a global wrapper methods which delegated
 // to the default client, create a request and SetPathParams for request.
 func SetPathParams(params map[string]string) *Request {
@@ -288,6 +375,18 @@ func SetBody(body interface{}) *Request {
 	return defaultClient.R().SetBody(body)
 }
 
+// SetBodyStream is a global wrapper methods which delegated
+// to the default client, create a request and SetBodyStream for request.
+func SetBodyStream(body io.Reader, contentType string, size int64) *Request {
+	return defaultClient.R().SetBodyStream(body, contentType, size)
+}
+
+// SetBodyChunked is a global wrapper methods which delegated
+// to the default client, create a request and SetBodyChunked for request.
+func SetBodyChunked(body io.Reader) *Request {
+	return defaultClient.R().SetBodyChunked(body)
+}
+
 // SetBodyBytes is a global wrapper methods which delegated
 // to the default client, create a request and SetBodyBytes for request.
 func SetBodyBytes(body []byte) *Request {
@@ -336,6 +435,36 @@ func SetBodyXmlMarshal(v interface{}) *Request {
 	return defaultClient.R().SetBodyXmlMarshal(v)
 }
 
+// SetBodyMsgpackBytes is a global wrapper methods which delegated
+// to the default client, create a request and SetBodyMsgpackBytes for request.
+func SetBodyMsgpackBytes(body []byte) *Request {
+	return defaultClient.R().SetBodyMsgpackBytes(body)
+}
+
+// SetBodyMsgpackMarshal is a global wrapper methods which delegated
+// to the default client, create a request and SetBodyMsgpackMarshal for request.
+func SetBodyMsgpackMarshal(v interface{}) *Request {
+	return defaultClient.R().SetBodyMsgpackMarshal(v)
+}
+
+// SetBodyProtobuf is a global wrapper methods which delegated
+// to the default client, create a request and SetBodyProtobuf for request.
+func SetBodyProtobuf(msg proto.Message) *Request {
+	return defaultClient.R().SetBodyProtobuf(msg)
+}
+
+// SetIfNoneMatch is a global wrapper methods which delegated
+// to the default client, create a request and SetIfNoneMatch for request.
+func SetIfNoneMatch(etag string) *Request {
+	return defaultClient.R().SetIfNoneMatch(etag)
+}
+
+// SetIfModifiedSince is a global wrapper methods which delegated
+// to the default client, create a request and SetIfModifiedSince for request.
+func SetIfModifiedSince(t time.Time) *Request {
+	return defaultClient.R().SetIfModifiedSince(t)
+}
+
 // SetContentType is a global wrapper methods which delegated
 // to the default client, create a request and SetContentType for request.
 func SetContentType(contentType string) *Request {
@@ -468,6 +597,18 @@ func SetRetryBackoffInterval(min, max time.Duration) *Request {
 	return defaultClient.R().SetRetryBackoffInterval(min, max)
 }
 
+// SetRetryExponentialInterval is a global wrapper methods which delegated
+// to the default client, create a request and SetRetryExponentialInterval for request.
+func SetRetryExponentialInterval(initial, max time.Duration, multiplier float64) *Request {
+	return defaultClient.R().SetRetryExponentialInterval(initial, max, multiplier)
+}
+
+// SetRetryMaxWait is a global wrapper methods which delegated
+// to the default client, create a request and SetRetryMaxWait for request.
+func SetRetryMaxWait(max time.Duration) *Request {
+	return defaultClient.R().SetRetryMaxWait(max)
+}
+
 // SetRetryHook is a global wrapper methods which delegated
 // to the default client, create a request and SetRetryHook for request.
 func SetRetryHook(hook RetryHookFunc) *Request {
@@ -492,6 +633,12 @@ func AddRetryCondition(condition RetryConditionFunc) *Request {
 	return defaultClient.R().AddRetryCondition(condition)
 }
 
+// AddRetryOnStatus is a global wrapper methods which delegated
+// to the default client, create a request and AddRetryOnStatus for request.
+func AddRetryOnStatus(codes ...int) *Request {
+	return defaultClient.R().AddRetryOnStatus(codes...)
+}
+
 // SetUploadCallback is a global wrapper methods which delegated
 // to the default client, create a request and SetUploadCallback for request.
 func SetUploadCallback(callback UploadCallback) *Request {
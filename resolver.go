@@ -0,0 +1,107 @@
+package req
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+type resolvedHost struct {
+	host     string
+	expireAt time.Time
+}
+
+// hostResolver resolves the host with the custom function before dialing and
+// caches the results for ttl, it is shared with the cloned clients.
+type hostResolver struct {
+	resolve func(host string) (string, error)
+	next    func(ctx context.Context, network, addr string) (net.Conn, error)
+	mu      sync.Mutex
+	ttl     time.Duration
+	cache   map[string]resolvedHost
+}
+
+func (r *hostResolver) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	host, err = r.lookup(host)
+	if err != nil {
+		return nil, err
+	}
+	return r.next(ctx, network, net.JoinHostPort(host, port))
+}
+
+func (r *hostResolver) setTTL(ttl time.Duration) {
+	r.mu.Lock()
+	r.ttl = ttl
+	r.cache = make(map[string]resolvedHost)
+	r.mu.Unlock()
+}
+
+func (r *hostResolver) lookup(host string) (string, error) {
+	r.mu.Lock()
+	ttl := r.ttl
+	if cached, ok := r.cache[host]; ok && time.Now().Before(cached.expireAt) {
+		r.mu.Unlock()
+		return cached.host, nil
+	}
+	r.mu.Unlock()
+
+	resolved, err := r.resolve(host)
+	if err != nil {
+		return "", err
+	}
+	if ttl > 0 {
+		r.mu.Lock()
+		r.cache[host] = resolvedHost{host: resolved, expireAt: time.Now().Add(ttl)}
+		r.mu.Unlock()
+	}
+	return resolved, nil
+}
+
+// SetHostResolver set the function which resolves the host to dial instead of
+// the system DNS, e.g. resolve the host through a service registry such as
+// Consul or etcd. The returned host (usually an IP address) is dialed with the
+// original port, and the dial fails with the error returned by fn. It wraps
+// the current `DialContext` function (see SetDial), so SetDial should be
+// called before it (calling SetDial after it removes the resolver), and it is
+// only valid for HTTP1 and HTTP2. The TLS server
+// name is still the original host, and the host of the proxy is resolved by
+// fn if a proxy is used.
+//
+// The results are not cached by default, see SetHostResolverTTL.
+func (c *Client) SetHostResolver(fn func(host string) (string, error)) *Client {
+	if fn == nil {
+		c.log.Warnf("ignore nil host resolver in SetHostResolver")
+		return c
+	}
+	next := c.Transport.DialContext
+	if c.hostResolver != nil {
+		next = c.hostResolver.next // replace the previous resolver.
+	}
+	if next == nil {
+		next = zeroDialer.DialContext
+	}
+	c.hostResolver = &hostResolver{
+		resolve: fn,
+		next:    next,
+		ttl:     c.hostResolverTTL,
+		cache:   make(map[string]resolvedHost),
+	}
+	c.Transport.SetDial(c.hostResolver.dial)
+	return c
+}
+
+// SetHostResolverTTL set the duration for which the results of the function
+// set by SetHostResolver are cached, the function is called on every dial if
+// ttl <= 0, which is the default. The cached results are dropped.
+func (c *Client) SetHostResolverTTL(ttl time.Duration) *Client {
+	c.hostResolverTTL = ttl
+	if c.hostResolver != nil {
+		c.hostResolver.setTTL(ttl)
+	}
+	return c
+}
@@ -0,0 +1,86 @@
+package req
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestSetHostResolver(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	}))
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	var calls atomic.Int32
+	errNotFound := errors.New("service not found")
+	resolve := func(host string) (string, error) {
+		calls.Add(1)
+		if host == "api.service.consul" {
+			return "127.0.0.1", nil
+		}
+		return "", errNotFound
+	}
+	url := "http://api.service.consul:" + port + "/"
+
+	// the connection is not reused so that every request dials.
+	c := C().DisableKeepAlives().SetHostResolver(resolve)
+	for i := 0; i < 2; i++ {
+		resp, err := c.R().Get(url)
+		assertSuccess(t, resp, err)
+		tests.AssertEqual(t, "api.service.consul:"+port, resp.String())
+	}
+	tests.AssertEqual(t, int32(2), calls.Load())
+
+	_, err := c.R().Get("http://unknown.service.consul:" + port)
+	tests.AssertEqual(t, true, errors.Is(err, errNotFound))
+
+	// the results are cached within the ttl.
+	calls.Store(0)
+	c.SetHostResolverTTL(time.Hour)
+	for i := 0; i < 3; i++ {
+		resp, err := c.R().Get(url)
+		assertSuccess(t, resp, err)
+	}
+	tests.AssertEqual(t, int32(1), calls.Load())
+
+	c.SetHostResolverTTL(time.Nanosecond)
+	for i := 0; i < 2; i++ {
+		resp, err := c.R().Get(url)
+		assertSuccess(t, resp, err)
+	}
+	tests.AssertEqual(t, int32(3), calls.Load())
+
+	// the previous resolver is replaced rather than wrapped.
+	c.SetHostResolver(func(host string) (string, error) {
+		return "127.0.0.1", nil
+	})
+	resp, err := c.R().Get("http://unknown.service.consul:" + port)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, int32(3), calls.Load())
+
+	// SetDial removes the resolver.
+	var dialed string
+	c.SetLogger(nil).SetDial(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = addr
+		return net.Dial(network, ts.Listener.Addr().String())
+	})
+	tests.AssertIsNil(t, c.hostResolver)
+	resp, err = c.R().Get(url)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "api.service.consul:"+port, dialed)
+
+	// the resolver wraps the dial function set before it.
+	c.SetHostResolver(resolve)
+	resp, err = c.R().Get(url)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "127.0.0.1:"+port, dialed)
+}
@@ -1,12 +1,24 @@
 package req
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"github.com/imroc/req/v3/internal/header"
 	"github.com/imroc/req/v3/internal/util"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 // Response is the http response.
@@ -20,9 +32,11 @@ type Response struct {
 	// Request is the Response's related Request.
 	Request    *Request
 	body       []byte
+	bodySaved  bool
 	receivedAt time.Time
 	error      interface{}
 	result     interface{}
+	tunnel     io.ReadWriteCloser
 }
 
 // IsSuccess method returns true if no error occurs and HTTP status `code >= 200 and <= 299`
@@ -63,6 +77,35 @@ func (r *Response) IsErrorState() bool {
 	return r.ResultState() == ErrorState
 }
 
+// IsRedirect method returns true if no error occurs and HTTP status `code >= 300 and <= 399`.
+func (r *Response) IsRedirect() bool {
+	return r.statusCodeBetween(300, 399)
+}
+
+// IsClientError method returns true if no error occurs and HTTP status `code >= 400 and <= 499`.
+func (r *Response) IsClientError() bool {
+	return r.statusCodeBetween(400, 499)
+}
+
+// IsServerError method returns true if no error occurs and HTTP status `code >= 500 and <= 599`.
+func (r *Response) IsServerError() bool {
+	return r.statusCodeBetween(500, 599)
+}
+
+// IsNotModified method returns true if no error occurs and HTTP status `code == 304`,
+// which is the response to the conditional request sent with Request.SetIfNoneMatch
+// or Request.SetIfModifiedSince if the resource is not modified.
+func (r *Response) IsNotModified() bool {
+	return r.statusCodeBetween(http.StatusNotModified, http.StatusNotModified)
+}
+
+func (r *Response) statusCodeBetween(min, max int) bool {
+	if r.Response == nil {
+		return false
+	}
+	return r.StatusCode >= min && r.StatusCode <= max
+}
+
 // GetContentType return the `Content-Type` header value.
 func (r *Response) GetContentType() string {
 	if r.Response == nil {
@@ -71,6 +114,36 @@ func (r *Response) GetContentType() string {
 	return r.Header.Get(header.ContentType)
 }
 
+// GetMediaType return the media type of the `Content-Type` header value without
+// the parameters (e.g. "text/html"), which is lowercased, empty if the header is
+// missing or invalid.
+func (r *Response) GetMediaType() string {
+	mediaType, _, err := mime.ParseMediaType(r.GetContentType())
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// GetCharset return the charset parameter of the `Content-Type` header value,
+// empty if it's not specified. Note it's the original charset of the body even
+// if the body is decoded to utf-8 automatically.
+func (r *Response) GetCharset() string {
+	_, params, err := mime.ParseMediaType(r.GetContentType())
+	if err != nil {
+		return ""
+	}
+	return params["charset"]
+}
+
+// GetContentLength return the length of the response body, -1 if it's unknown.
+func (r *Response) GetContentLength() int64 {
+	if r.Response == nil {
+		return -1
+	}
+	return r.ContentLength
+}
+
 // ResultState returns the result state.
 // By default, it returns SuccessState if HTTP status `code >= 400`, and returns
 // ErrorState if HTTP status `code >= 400`, otherwise returns UnknownState.
@@ -121,7 +194,10 @@ func (r *Response) ErrorResult() interface{} {
 	return r.error
 }
 
-// TraceInfo returns the TraceInfo from Request.
+// TraceInfo returns the TraceInfo from Request, which has the timing data
+// like DNS lookup, TCP connect, TLS handshake and server processing time, the
+// trace is collected only if Client.EnableTraceAll or Request.EnableTrace is
+// called, otherwise the zero TraceInfo is returned.
 func (r *Response) TraceInfo() TraceInfo {
 	return r.Request.TraceInfo()
 }
@@ -173,6 +249,46 @@ func (r *Response) UnmarshalXml(v interface{}) error {
 	return r.Request.client.xmlUnmarshal(b, v)
 }
 
+// UnmarshalMsgpack unmarshalls MessagePack response body into the specified
+// object.
+func (r *Response) UnmarshalMsgpack(v interface{}) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	b, err := r.ToBytes()
+	if err != nil {
+		return err
+	}
+	return r.Request.client.msgpackUnmarshal(b, v)
+}
+
+// UnmarshalProtobuf unmarshalls Protobuf response body into the specified
+// message.
+func (r *Response) UnmarshalProtobuf(msg proto.Message) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	b, err := r.ToBytes()
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, msg)
+}
+
+// DumpProtobuf returns the Protobuf response body in the text format, which is
+// decoded with the message descriptor desc, it is useful to inspect the
+// response body if the generated message type is not available, e.g.
+//
+//	fd, _ := protoregistry.GlobalFiles.FindDescriptorByName("foo.Bar")
+//	text, err := resp.DumpProtobuf(fd.(protoreflect.MessageDescriptor))
+func (r *Response) DumpProtobuf(desc protoreflect.MessageDescriptor) (string, error) {
+	msg := dynamicpb.NewMessage(desc)
+	if err := r.UnmarshalProtobuf(msg); err != nil {
+		return "", err
+	}
+	return prototext.MarshalOptions{Multiline: true}.Format(msg), nil
+}
+
 // Unmarshal unmarshalls response body into the specified object according
 // to response `Content-Type`.
 func (r *Response) Unmarshal(v interface{}) error {
@@ -185,6 +301,10 @@ func (r *Response) Unmarshal(v interface{}) error {
 		return r.UnmarshalJson(v)
 	} else if strings.Contains(contentType, "xml") {
 		return r.UnmarshalXml(v)
+	} else if util.IsMsgpackType(contentType) {
+		return r.UnmarshalMsgpack(v)
+	} else if msg, ok := v.(proto.Message); ok && util.IsProtobufType(contentType) {
+		return r.UnmarshalProtobuf(msg)
 	}
 	return r.UnmarshalJson(v)
 }
@@ -200,6 +320,8 @@ func (r *Response) Into(v interface{}) error {
 //  1. `Request.SetResult` or `Request.SetError` is called.
 //  2. `Client.DisableAutoReadResponse` and `Request.DisableAutoReadResponse` is not
 //     called, and also `Request.SetOutput` and `Request.SetOutputFile` is not called.
+//
+// Use ToBytes to read the body if it's not read yet.
 func (r *Response) Bytes() []byte {
 	return r.body
 }
@@ -209,10 +331,143 @@ func (r *Response) Bytes() []byte {
 //  1. `Request.SetResult` or `Request.SetError` is called.
 //  2. `Client.DisableAutoReadResponse` and `Request.DisableAutoReadResponse` is not
 //     called, and also `Request.SetOutput` and `Request.SetOutputFile` is not called.
+//
+// Use ToString to read the body if it's not read yet.
 func (r *Response) String() string {
 	return string(r.body)
 }
 
+// HTTPError is the error of the response with error state, which is
+// returned if CheckErrorStatus is set by Client.SetResponseStatusCheckFunc.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	// Body is the raw response body, nil if it is not auto-read.
+	Body []byte
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	if len(e.Body) == 0 {
+		return "http error: " + e.Status
+	}
+	return "http error: " + e.Status + ": " + string(e.Body)
+}
+
+// CheckErrorStatus returns *HTTPError if the response is in error state,
+// which by default means HTTP status `code >= 400`, see Client.SetResultStateCheckFunc.
+// It can be used by Client.SetResponseStatusCheckFunc.
+func CheckErrorStatus(resp *Response) error {
+	if !resp.IsErrorState() {
+		return nil
+	}
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       resp.body,
+	}
+}
+
+// StatusMismatchError is returned if the response status code is not one of
+// the codes set by Request.ExpectStatus.
+type StatusMismatchError struct {
+	Expected []int
+	Actual   int
+}
+
+// Error implements the error interface.
+func (e *StatusMismatchError) Error() string {
+	return fmt.Sprintf("unexpected status code %d, expected %v", e.Actual, e.Expected)
+}
+
+func checkExpectedStatus(resp *Response, expected []int) error {
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+	return &StatusMismatchError{Expected: expected, Actual: resp.StatusCode}
+}
+
+// ErrBodyAlreadyRead is returned by Response.SaveBody, Response.SaveBodyAppend and
+// Response.HTML if the response body which is not auto-read has been consumed by
+// a previous call.
+var ErrBodyAlreadyRead = errors.New("response body has already been read")
+
+// saveBodyReader returns the reader of the response body, which is the read
+// body if it has been read, otherwise the underlying body stream.
+func (r *Response) saveBodyReader() (io.ReadCloser, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	if r.body != nil {
+		return io.NopCloser(bytes.NewReader(r.body)), nil
+	}
+	if r.Response == nil || r.Response.Body == nil {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	if r.bodySaved {
+		return nil, ErrBodyAlreadyRead
+	}
+	r.bodySaved = true
+	return r.Body, nil
+}
+
+// SaveBody saves the response body to the file, the body is written to a
+// temporary file in the same directory first, which is renamed to the file
+// after all content is written, so the file is never partially written. The
+// body is streamed to the file if it is not auto-read (e.g. DisableAutoReadResponse
+// is called), in which case it can only be saved once.
+func (r *Response) SaveBody(file string) (err error) {
+	body, err := r.saveBodyReader()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if err = util.CreateDirectory(filepath.Dir(file)); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+	_, err = io.Copy(f, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), file)
+}
+
+// SaveBodyAppend is similar to SaveBody, but appends the response body to
+// the file (created if not exists), which is useful for log-style responses.
+func (r *Response) SaveBodyAppend(file string) (err error) {
+	body, err := r.saveBodyReader()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if err = util.CreateDirectory(filepath.Dir(file)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
 // ToString returns the response body as string, read body if not have been read.
 func (r *Response) ToString() (string, error) {
 	b, err := r.ToBytes()
@@ -220,6 +475,8 @@ func (r *Response) ToString() (string, error) {
 }
 
 // ToBytes returns the response body as []byte, read body if not have been read.
+// The body is closed after read and cached in the response, so the subsequent
+// calls of ToBytes, ToString, Bytes, String and Unmarshal return the cached body.
 func (r *Response) ToBytes() (body []byte, err error) {
 	if r.Err != nil {
 		return nil, r.Err
@@ -290,3 +547,163 @@ func (r *Response) HeaderToString() string {
 	}
 	return convertHeaderToString(r.Header)
 }
+
+// HeaderMap returns the headers as a map keyed by the lowercased header name,
+// only the first value is kept if the header has multiple values, use
+// GetHeaderValues to get all of them.
+func (r *Response) HeaderMap() map[string]string {
+	m := make(map[string]string)
+	if r.Response == nil {
+		return m
+	}
+	for k, vs := range r.Header {
+		if len(vs) > 0 {
+			m[strings.ToLower(k)] = vs[0]
+		}
+	}
+	return m
+}
+
+// Cookie returns the first cookie with the given name in the `Set-Cookie`
+// headers of the response, or http.ErrNoCookie if not found. It works even if
+// the cookie jar is disabled.
+func (r *Response) Cookie(name string) (*http.Cookie, error) {
+	if r.Response == nil {
+		return nil, http.ErrNoCookie
+	}
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name {
+			return cookie, nil
+		}
+	}
+	return nil, http.ErrNoCookie
+}
+
+// CookieMap returns the cookies in the `Set-Cookie` headers of the response
+// keyed by the cookie name, only the first one is kept if there are multiple
+// cookies with the same name.
+func (r *Response) CookieMap() map[string]*http.Cookie {
+	m := make(map[string]*http.Cookie)
+	if r.Response == nil {
+		return m
+	}
+	for _, cookie := range r.Cookies() {
+		if _, ok := m[cookie.Name]; !ok {
+			m[cookie.Name] = cookie
+		}
+	}
+	return m
+}
+
+// Links parses the `Link` headers (RFC 8288, formerly RFC 5988) and returns
+// the target URLs keyed by the relation type, e.g. "next", "prev", "first"
+// and "last". Relative URLs are resolved against the request URL. If several
+// links have the same relation type, the first one is returned.
+func (r *Response) Links() map[string]*url.URL {
+	links := make(map[string]*url.URL)
+	if r.Response == nil {
+		return links
+	}
+	var base *url.URL
+	if r.Response.Request != nil {
+		base = r.Response.Request.URL
+	}
+	for _, value := range r.Header.Values("Link") {
+		for _, link := range splitLinkHeader(value) {
+			target, rels, ok := parseLink(link)
+			if !ok {
+				continue
+			}
+			u, err := url.Parse(target)
+			if err != nil {
+				continue
+			}
+			if base != nil {
+				u = base.ResolveReference(u)
+			}
+			for _, rel := range rels {
+				if _, ok := links[rel]; !ok {
+					links[rel] = u
+				}
+			}
+		}
+	}
+	return links
+}
+
+// NextPageURL returns the URL of the link with "next" relation type, which
+// is commonly used for pagination.
+func (r *Response) NextPageURL() (*url.URL, bool) {
+	u, ok := r.Links()["next"]
+	return u, ok
+}
+
+// RedirectURL returns the URL of the `Location` header of the redirect response,
+// which is useful if the redirect is not followed (see Client.DisableAutoRedirect).
+// The relative URL is resolved against the request URL (RFC 9110, Section 10.2.2).
+// It returns false if the response is not a redirect (3xx) or the `Location`
+// header is absent or invalid.
+func (r *Response) RedirectURL() (*url.URL, bool) {
+	if !r.IsRedirect() {
+		return nil, false
+	}
+	u, err := r.Location()
+	if err != nil {
+		return nil, false
+	}
+	return u, true
+}
+
+// splitLinkHeader splits the Link header value into links, the commas
+// inside the URL or quoted parameter values are not treated as separators.
+func splitLinkHeader(value string) []string {
+	var links []string
+	inURL, inQuote := false, false
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case inQuote:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inQuote = false
+			}
+		case c == '<':
+			inURL = true
+		case c == '>':
+			inURL = false
+		case c == '"' && !inURL:
+			inQuote = true
+		case c == ',' && !inURL:
+			links = append(links, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(links, value[start:])
+}
+
+// parseLink parses a single link like `<https://example.com/?page=2>; rel="next"`,
+// and returns the target URL and the lowercase relation types.
+func parseLink(link string) (target string, rels []string, ok bool) {
+	link = strings.TrimSpace(link)
+	if !strings.HasPrefix(link, "<") {
+		return
+	}
+	end := strings.IndexByte(link, '>')
+	if end < 0 {
+		return
+	}
+	target = link[1:end]
+	for _, param := range strings.Split(link[end+1:], ";") {
+		name, value, found := strings.Cut(param, "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		for _, rel := range strings.Fields(value) {
+			rels = append(rels, strings.ToLower(rel))
+		}
+		break
+	}
+	return target, rels, len(rels) > 0
+}
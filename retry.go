@@ -3,6 +3,8 @@ package req
 import (
 	"math"
 	"math/rand"
+	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -32,6 +34,23 @@ func backoffInterval(min, max time.Duration) GetRetryIntervalFunc {
 	}
 }
 
+func exponentialInterval(initial, max time.Duration, multiplier float64) GetRetryIntervalFunc {
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	base := float64(initial)
+	return func(resp *Response, attempt int) time.Duration {
+		temp := base * math.Pow(multiplier, float64(attempt-1))
+		if max > 0 && temp > float64(max) {
+			return max
+		}
+		if temp >= math.MaxInt64 { // uncapped interval overflows time.Duration.
+			return math.MaxInt64
+		}
+		return time.Duration(temp)
+	}
+}
+
 func newDefaultRetryOption() *retryOption {
 	return &retryOption{
 		GetRetryInterval: defaultGetRetryInterval,
@@ -43,6 +62,68 @@ type retryOption struct {
 	GetRetryInterval GetRetryIntervalFunc
 	RetryConditions  []RetryConditionFunc
 	RetryHooks       []RetryHookFunc
+	MaxWait          time.Duration
+	// RespectRetryAfter uses the delay in the `Retry-After` header of the
+	// response as the interval if it's present.
+	RespectRetryAfter bool
+}
+
+// retryInterval returns the interval before the next attempt, which is capped
+// at MaxWait plus a random jitter of up to 10% to avoid the thundering herd.
+func (ro *retryOption) retryInterval(resp *Response, attempt int) time.Duration {
+	interval, ok := time.Duration(0), false
+	if ro.RespectRetryAfter {
+		interval, ok = retryAfter(resp)
+	}
+	if !ok {
+		interval = ro.GetRetryInterval(resp, attempt)
+	}
+	if ro.MaxWait > 0 && interval > ro.MaxWait {
+		interval = ro.MaxWait + time.Duration(rand.Int63n(int64(ro.MaxWait/10)+1))
+	}
+	return interval
+}
+
+// retryOnStatus returns the RetryConditionFunc which retries if the response
+// status code is one of codes.
+func retryOnStatus(codes []int) RetryConditionFunc {
+	return func(resp *Response, err error) bool {
+		if err != nil || resp == nil || resp.Response == nil {
+			return false
+		}
+		for _, code := range codes {
+			if resp.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// retryAfter returns the delay in the `Retry-After` header of the response,
+// which is either the delay seconds or the HTTP date.
+func retryAfter(resp *Response) (time.Duration, bool) {
+	if resp == nil || resp.Response == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := time.Until(t); d > 0 {
+		return d, true
+	}
+	return 0, true
 }
 
 func (ro *retryOption) Clone() *retryOption {
@@ -50,8 +131,10 @@ func (ro *retryOption) Clone() *retryOption {
 		return nil
 	}
 	o := &retryOption{
-		MaxRetries:       ro.MaxRetries,
-		GetRetryInterval: ro.GetRetryInterval,
+		MaxRetries:        ro.MaxRetries,
+		GetRetryInterval:  ro.GetRetryInterval,
+		MaxWait:           ro.MaxWait,
+		RespectRetryAfter: ro.RespectRetryAfter,
 	}
 	o.RetryConditions = append(o.RetryConditions, ro.RetryConditions...)
 	o.RetryHooks = append(o.RetryHooks, ro.RetryHooks...)
@@ -2,9 +2,14 @@ package req
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"math"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -34,6 +39,76 @@ func testRetry(t *testing.T, setFunc func(r *Request)) {
 	tests.AssertEqual(t, 3, attempt)
 }
 
+func TestRetryExponentialInterval(t *testing.T) {
+	testRetry(t, func(r *Request) {
+		r.SetRetryExponentialInterval(1*time.Millisecond, 10*time.Millisecond, 2)
+	})
+	fn := exponentialInterval(10*time.Millisecond, 50*time.Millisecond, 2)
+	tests.AssertEqual(t, 10*time.Millisecond, fn(nil, 1))
+	tests.AssertEqual(t, 40*time.Millisecond, fn(nil, 3))
+	tests.AssertEqual(t, 50*time.Millisecond, fn(nil, 4))
+
+	// uncapped if max <= 0.
+	fn = exponentialInterval(10*time.Millisecond, 0, 2)
+	tests.AssertEqual(t, 10*time.Millisecond, fn(nil, 1))
+	tests.AssertEqual(t, 80*time.Millisecond, fn(nil, 4))
+	tests.AssertEqual(t, time.Duration(math.MaxInt64), fn(nil, 1000))
+	tests.AssertEqual(t, time.Duration(math.MaxInt64), fn(nil, 10000)) // math.Pow returns +Inf
+	fn = exponentialInterval(10*time.Millisecond, -time.Second, 2)
+	tests.AssertEqual(t, 20*time.Millisecond, fn(nil, 2))
+
+	// capped at max even if the interval overflows time.Duration.
+	fn = exponentialInterval(10*time.Millisecond, time.Hour, 2)
+	tests.AssertEqual(t, time.Hour, fn(nil, 10000))
+}
+
+func TestRetryMaxWait(t *testing.T) {
+	testRetry(t, func(r *Request) {
+		r.SetRetryFixedInterval(time.Hour).SetRetryMaxWait(time.Millisecond)
+	})
+
+	ro := &retryOption{
+		GetRetryInterval: exponentialInterval(10*time.Millisecond, time.Hour, 10),
+		MaxWait:          100 * time.Millisecond,
+	}
+	tests.AssertEqual(t, 10*time.Millisecond, ro.retryInterval(nil, 1))
+	tests.AssertEqual(t, 100*time.Millisecond, ro.retryInterval(nil, 2))
+	for i := 0; i < 100; i++ {
+		interval := ro.retryInterval(nil, 5)
+		tests.AssertEqual(t, true, interval >= 100*time.Millisecond && interval <= 110*time.Millisecond)
+	}
+
+	c := tc().SetCommonRetryMaxWait(time.Second)
+	tests.AssertEqual(t, time.Second, c.R().getRetryOption().MaxWait)
+}
+
+func TestRetryRespectContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	resp, err := tc().R().
+		SetContext(ctx).
+		SetRetryCount(3).
+		SetRetryFixedInterval(time.Second).
+		AddRetryCondition(func(resp *Response, err error) bool {
+			return resp.StatusCode == http.StatusTooManyRequests
+		}).Get("/too-many")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, http.StatusTooManyRequests, resp.StatusCode)
+	tests.AssertEqual(t, 0, resp.Request.RetryAttempt)
+}
+
+func TestRetryDump(t *testing.T) {
+	resp, err := tc().R().
+		EnableDump().
+		SetRetryCount(1).
+		SetRetryFixedInterval(time.Millisecond).
+		AddRetryCondition(func(resp *Response, err error) bool {
+			return resp.StatusCode == http.StatusTooManyRequests
+		}).Get("/too-many")
+	tests.AssertNoError(t, err)
+	tests.AssertContains(t, resp.Dump(), "retry attempt 1", true)
+}
+
 func TestRetryInterval(t *testing.T) {
 	testRetry(t, func(r *Request) {
 		r.SetRetryInterval(func(resp *Response, attempt int) time.Duration {
@@ -115,6 +190,67 @@ func TestAddRetryCondition(t *testing.T) {
 
 }
 
+func TestAddRetryOnStatus(t *testing.T) {
+	var intervals []time.Duration
+	resp, err := tc().R().
+		SetRetryCount(2).
+		SetRetryFixedInterval(time.Millisecond).
+		AddRetryOnStatus(http.StatusServiceUnavailable, http.StatusTooManyRequests).
+		SetRetryHook(func(resp *Response, err error) {
+			intervals = append(intervals, resp.Request.retryOption.retryInterval(resp, resp.Request.RetryAttempt))
+		}).Get("/too-many")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, 2, resp.Request.RetryAttempt)
+	tests.AssertEqual(t, []time.Duration{time.Millisecond, time.Millisecond}, intervals)
+
+	resp, err = tc().SetCommonRetryCount(2).AddCommonRetryOnStatus(http.StatusServiceUnavailable).R().Get("/too-many")
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, 0, resp.Request.RetryAttempt)
+
+	ro := tc().AddCommonRetryOnStatus(http.StatusTooManyRequests).R().getRetryOption()
+	tests.AssertEqual(t, true, ro.RespectRetryAfter)
+	retryAfterResp := func(v string) *Response {
+		return &Response{Response: &http.Response{Header: http.Header{"Retry-After": {v}}}}
+	}
+	tests.AssertEqual(t, 3*time.Second, ro.retryInterval(retryAfterResp("3"), 1))
+	interval := ro.retryInterval(retryAfterResp(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)), 1)
+	tests.AssertEqual(t, true, interval > 58*time.Second && interval <= time.Minute)
+	tests.AssertEqual(t, defaultGetRetryInterval(nil, 1), ro.retryInterval(retryAfterResp("invalid"), 1))
+	ro.MaxWait = time.Second
+	interval = ro.retryInterval(retryAfterResp("3"), 1)
+	tests.AssertEqual(t, true, interval >= time.Second && interval <= 1100*time.Millisecond)
+}
+
+func TestRetryConditionsORed(t *testing.T) {
+	var attempts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := attempts.Add(1)
+		if attempt == 1 {
+			time.Sleep(100 * time.Millisecond) // timeout
+		}
+		if attempt == 2 {
+			w.Write([]byte("retry"))
+			return
+		}
+		w.Write([]byte("done"))
+	}))
+	defer ts.Close()
+
+	resp, err := C().SetTimeout(50 * time.Millisecond).
+		SetCommonRetryCount(3).
+		SetCommonRetryFixedInterval(time.Millisecond).
+		AddCommonRetryCondition(func(resp *Response, err error) bool {
+			var netErr net.Error
+			return errors.As(err, &netErr) && netErr.Timeout()
+		}).
+		AddCommonRetryCondition(func(resp *Response, err error) bool {
+			return err == nil && resp.String() == "retry"
+		}).R().Get(ts.URL)
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "done", resp.String())
+	tests.AssertEqual(t, 2, resp.Request.RetryAttempt)
+}
+
 func TestRetryWithUnreplayableBody(t *testing.T) {
 	_, err := tc().R().
 		SetRetryCount(1).
@@ -0,0 +1,80 @@
+package req
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/imroc/req/v3/internal/header"
+)
+
+// SignerFunc signs the canonical request and returns the signature.
+//
+// The canonical request consists of the following lines separated by "\n":
+// the request method, the request URL, one "name:value" line for each header
+// sorted by the lower-cased header name (multiple values are joined with ","),
+// an empty line, and the hex encoded SHA256 hash of the request body. The
+// "Authorization" header is not included.
+type SignerFunc func(canonicalRequest []byte) (string, error)
+
+// HMACSigner returns a SignerFunc which signs the canonical request with
+// HMAC-SHA256 using the key, the signature is hex encoded.
+func HMACSigner(key []byte) SignerFunc {
+	return func(canonicalRequest []byte) (string, error) {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(canonicalRequest)
+		return hex.EncodeToString(mac.Sum(nil)), nil
+	}
+}
+
+type requestSigner struct {
+	keyID  string
+	signer SignerFunc
+}
+
+// canonicalRequest builds the canonical request which is described in
+// SignerFunc, body is the raw request body.
+func canonicalRequest(req *http.Request, body []byte) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString(req.Method)
+	buf.WriteByte('\n')
+	buf.WriteString(req.URL.String())
+	buf.WriteByte('\n')
+	names := make([]string, 0, len(req.Header))
+	values := make(map[string]string, len(req.Header))
+	for k, vs := range req.Header {
+		if strings.EqualFold(k, header.Authorization) {
+			continue
+		}
+		name := strings.ToLower(k)
+		if _, ok := values[name]; !ok {
+			names = append(names, name)
+		}
+		values[name] = strings.Join(vs, ",")
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		buf.WriteString(name)
+		buf.WriteByte(':')
+		buf.WriteString(strings.TrimSpace(values[name]))
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	sum := sha256.Sum256(body)
+	buf.WriteString(hex.EncodeToString(sum[:]))
+	return buf.Bytes()
+}
+
+func (s *requestSigner) sign(req *http.Request, body []byte) error {
+	signature, err := s.signer(canonicalRequest(req, body))
+	if err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+	req.Header.Set(header.Authorization, fmt.Sprintf(`Signature keyId="%s",signature="%s"`, s.keyID, signature))
+	return nil
+}
@@ -0,0 +1,51 @@
+package req
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func TestSetRequestSigner(t *testing.T) {
+	key := []byte("secret")
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	var canonical []byte
+	c := C().SetUserAgent("").SetRequestSigner("mykey", "", func(canonicalRequest []byte) (string, error) {
+		canonical = canonicalRequest
+		return HMACSigner(key)(canonicalRequest)
+	})
+	resp, err := c.R().SetHeader("X-Foo", "bar").SetBody("test").Post(server.URL + "/path?a=b")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, "POST\n"+server.URL+"/path?a=b\ncontent-type:text/plain; charset=utf-8\nuser-agent:\nx-foo:bar\n\n"+
+		"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", string(canonical))
+	signature, _ := HMACSigner(key)(canonical)
+	tests.AssertEqual(t, `Signature keyId="mykey",signature="`+signature+`"`, auth)
+
+	resp, err = c.SetRequestSigner("mykey", string(key), nil).R().SetHeader("X-Foo", "bar").SetBody("test").Post(server.URL + "/path?a=b")
+	assertSuccess(t, resp, err)
+	tests.AssertEqual(t, `Signature keyId="mykey",signature="`+signature+`"`, auth)
+
+	signErr := errors.New("sign failed")
+	_, err = c.SetRequestSigner("mykey", "", func(canonicalRequest []byte) (string, error) {
+		return "", signErr
+	}).R().Get(server.URL)
+	tests.AssertEqual(t, true, errors.Is(err, signErr))
+}
+
+func TestCanonicalRequest(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/a?b=c", nil)
+	req.Header.Set("X-B", "2")
+	req.Header.Add("X-A", "1")
+	req.Header.Add("X-A", "3")
+	req.Header.Set("Authorization", "ignored")
+	tests.AssertEqual(t, "GET\nhttp://example.com/a?b=c\nx-a:1,3\nx-b:2\n\n"+
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", string(canonicalRequest(req, nil)))
+}
@@ -0,0 +1,104 @@
+package req
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type singleFlightResult struct {
+	resp *http.Response
+	body []byte
+}
+
+// isSingleFlight reports whether the request should be deduplicated, the
+// response body must be auto-read so that it can be shared, and the request
+// body must be in memory so that it can be compared.
+func (c *Client) isSingleFlight(r *Request) bool {
+	if c.singleFlightGroup == nil {
+		return false
+	}
+	if c.disableAutoReadResponse || r.disableAutoReadResponse || r.isSaveResponse {
+		return false
+	}
+	if r.Body == nil && r.GetBody != nil { // streamed body
+		return false
+	}
+	return r.singleFlight || r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+// singleFlightKey returns the key of the request which consists of the
+// method, the url, all the headers (including the credentials such as
+// Authorization and Cookie) and the hash of the body, so the requests sent
+// as different users never share the response.
+func singleFlightKey(r *Request) string {
+	req := r.RawRequest
+	var b strings.Builder
+	b.WriteString(req.Method)
+	b.WriteByte(' ')
+	b.WriteString(req.URL.String())
+	b.WriteByte('\n')
+	b.WriteString(req.Host)
+	b.WriteByte('\n')
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range req.Header[name] {
+			b.WriteString(name)
+			b.WriteByte(':')
+			b.WriteString(v)
+			b.WriteByte('\n')
+		}
+	}
+	sum := sha256.Sum256(r.Body)
+	b.WriteString(hex.EncodeToString(sum[:]))
+	return b.String()
+}
+
+// doSingleFlight sends the request, or waits for the in-flight request with
+// the same key (see singleFlightKey), every caller gets its own copy of the
+// response header and body. The shared request is sent with a context which
+// is not canceled with the caller who sends it, and each caller stops waiting
+// once its own context is done.
+func (c *Client) doSingleFlight(r *Request) (*http.Response, error) {
+	ctx := r.RawRequest.Context()
+	ch := c.singleFlightGroup.DoChan(singleFlightKey(r), func() (interface{}, error) {
+		req := r.RawRequest.WithContext(context.WithoutCancel(ctx))
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return &singleFlightResult{resp: resp, body: body}, nil
+	})
+	var result singleflight.Result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result = <-ch:
+	}
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	shared := result.Val.(*singleFlightResult)
+	resp := *shared.resp
+	resp.Header = shared.resp.Header.Clone()
+	resp.Trailer = shared.resp.Trailer.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(bytes.Clone(shared.body)))
+	resp.Request = r.RawRequest
+	return &resp, nil
+}
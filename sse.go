@@ -0,0 +1,173 @@
+package req
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imroc/req/v3/internal/dump"
+	"github.com/imroc/req/v3/internal/header"
+	"github.com/imroc/req/v3/internal/util"
+)
+
+// SSEEvent is an event received from a server-sent events stream.
+type SSEEvent struct {
+	// ID is the value of the "id" field, which will be sent back in the
+	// "Last-Event-ID" header when reconnecting.
+	ID string
+	// Event is the value of the "event" field, which is the type of the
+	// event, empty means "message".
+	Event string
+	// Data is the value of the "data" field, multiple data lines are
+	// joined with "\n".
+	Data string
+	// Retry is the reconnection time which sent by the "retry" field,
+	// zero if the event does not contain it.
+	Retry time.Duration
+}
+
+const eventStreamContentType = "text/event-stream"
+
+// DoSSE fires the request and reads the response as server-sent events
+// (text/event-stream), events are parsed incrementally and sent to the
+// returned event channel. If the server has sent a reconnection time
+// with the "retry" field, the request will be resent with the
+// "Last-Event-ID" header after the reconnection time when the connection
+// is broken unexpectedly. Both channels are closed when ctx is done or
+// the server closes the stream, the error channel receives at most one
+// error if failed.
+//
+// Use Request.EnableDump or Client.EnableDumpAll to dump the raw event
+// stream.
+func (r *Request) DoSSE(ctx context.Context) (<-chan SSEEvent, <-chan error) {
+	events := make(chan SSEEvent)
+	errs := make(chan error, 1)
+	if r.Method == "" {
+		r.Method = http.MethodGet
+	}
+	if d := r.Context().Value(dump.DumperKey); d != nil { // keep the request-level dumper
+		ctx = context.WithValue(ctx, dump.DumperKey, d)
+	}
+	r.SetHeader("Accept", eventStreamContentType).
+		SetHeader("Cache-Control", "no-cache").
+		DisableAutoReadResponse()
+	go func() {
+		defer close(errs)
+		defer close(events)
+		if err := r.readSSE(ctx, events); err != nil && ctx.Err() == nil {
+			errs <- err
+		}
+	}()
+	return events, errs
+}
+
+func (r *Request) readSSE(ctx context.Context, events chan<- SSEEvent) error {
+	var lastEventID string
+	var retry time.Duration
+	for {
+		resp := r.Do(ctx)
+		if resp.Err != nil {
+			return resp.Err
+		}
+		if resp.IsErrorState() {
+			resp.Body.Close()
+			return fmt.Errorf("sse: bad response status: %s", resp.Status)
+		}
+		if ct := resp.GetContentType(); !strings.HasPrefix(ct, eventStreamContentType) {
+			resp.Body.Close()
+			return fmt.Errorf("sse: bad response %s: %q", header.ContentType, ct)
+		}
+		err := parseSSE(ctx, resp.Body, &retry, func(event SSEEvent) bool {
+			if event.ID != "" {
+				lastEventID = event.ID
+			}
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+		resp.Body.Close()
+		if err == nil || ctx.Err() != nil || retry <= 0 { // stream is closed by server or ctx, or reconnection is not expected.
+			return err
+		}
+		if r.client.DebugLog {
+			r.client.log.Debugf("<sse> stream is broken (%v), reconnect in %v", err, retry)
+		}
+		select {
+		case <-time.After(retry):
+		case <-ctx.Done():
+			return nil
+		}
+		if lastEventID != "" {
+			r.SetHeader("Last-Event-ID", lastEventID)
+		}
+	}
+}
+
+// parseSSE parses the event stream from body and invokes onEvent for each
+// event, returns nil if the stream ends normally or onEvent returns false.
+// The latest reconnection time is stored into retry.
+func parseSSE(ctx context.Context, body io.Reader, retry *time.Duration, onEvent func(event SSEEvent) bool) error {
+	reader := bufio.NewReader(body)
+	var event SSEEvent
+	var data strings.Builder
+	hasData := false
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && (len(line) == 0 || !errors.Is(err, io.EOF)) {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 { // blank line, dispatch the event.
+			if hasData {
+				event.Data = data.String()
+				if !onEvent(event) {
+					return nil
+				}
+			}
+			event = SSEEvent{ID: event.ID}
+			data.Reset()
+			hasData = false
+			continue
+		}
+		if line[0] == ':' { // comment
+			continue
+		}
+		field, value, _ := util.CutBytes(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "event":
+			event.Event = string(value)
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.Write(value)
+			hasData = true
+		case "id":
+			if bytes.IndexByte(value, 0) < 0 {
+				event.ID = string(value)
+			}
+		case "retry":
+			if ms, err := strconv.ParseInt(string(value), 10, 64); err == nil && ms >= 0 {
+				event.Retry = time.Duration(ms) * time.Millisecond
+				*retry = event.Retry
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package req
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+func createSSEServer(stream string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, line := range strings.SplitAfter(stream, "\n") {
+			w.Write([]byte(line))
+			w.(http.Flusher).Flush()
+		}
+	}))
+}
+
+func TestDoSSE(t *testing.T) {
+	stream := ": comment\n" +
+		"id: 1\nevent: greeting\ndata: hello\ndata: world\n\n" +
+		"retry: 3000\ndata:no space\r\n\r\n" +
+		"data: incomplete"
+	server := createSSEServer(stream)
+	defer server.Close()
+
+	buf := new(bytes.Buffer)
+	events, errs := C().R().EnableDumpTo(buf).SetURL(server.URL).DoSSE(context.Background())
+	var got []SSEEvent
+	for event := range events {
+		got = append(got, event)
+	}
+	tests.AssertNoError(t, <-errs)
+	tests.AssertEqual(t, 2, len(got))
+	tests.AssertEqual(t, SSEEvent{ID: "1", Event: "greeting", Data: "hello\nworld"}, got[0])
+	tests.AssertEqual(t, SSEEvent{ID: "1", Data: "no space", Retry: 3 * time.Second}, got[1])
+	tests.AssertContains(t, buf.String(), "data: hello", true)
+}
+
+func TestDoSSECancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: first\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := C().R().SetURL(server.URL).DoSSE(ctx)
+	event := <-events
+	tests.AssertEqual(t, "first", event.Data)
+	cancel()
+	for range events {
+	}
+	tests.AssertNoError(t, <-errs)
+}
+
+func TestDoSSEBadContentType(t *testing.T) {
+	events, errs := tc().R().SetURL("/").DoSSE(context.Background())
+	for range events {
+	}
+	tests.AssertErrorContains(t, <-errs, "sse: bad response Content-Type")
+}
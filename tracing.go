@@ -0,0 +1,60 @@
+package req
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/imroc/req/v3"
+
+// EnableTracing enables the OpenTelemetry tracing, a client span is created
+// for each attempt of the request as the child of the span in the request
+// context, and the span context is propagated to the server by the W3C
+// `traceparent` and `tracestate` headers. The span is named like "HTTP GET"
+// with the `http.method`, `http.url` and `http.status_code` attributes as
+// otelhttp does. The global TracerProvider is used if tp is nil.
+func (c *Client) EnableTracing(tp trace.TracerProvider) *Client {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	wrapped := c.tracer != nil
+	c.tracer = tp.Tracer(tracerName)
+	if wrapped {
+		return c
+	}
+	return c.WrapRoundTripFunc(func(rt RoundTripper) RoundTripFunc {
+		return func(req *Request) (*Response, error) {
+			return req.client.traceRoundTrip(rt, req)
+		}
+	})
+}
+
+func (c *Client) traceRoundTrip(rt RoundTripper, req *Request) (resp *Response, err error) {
+	parent := req.Context()
+	ctx, span := c.tracer.Start(parent, "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", req.URL.String()),
+		),
+	)
+	defer span.End()
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Headers))
+	req.ctx = ctx
+	resp, err = rt.RoundTrip(req)
+	req.ctx = parent // the span of the next attempt is not the child of this one.
+	if resp != nil && resp.Response != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return
+}
@@ -0,0 +1,136 @@
+package req
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3/internal/tests"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingTracerProvider is a minimal trace.TracerProvider which records the
+// ended spans, so the tests do not depend on the OpenTelemetry SDK.
+type recordingTracerProvider struct {
+	embedded.TracerProvider
+	tracer recordingTracer
+}
+
+func (tp *recordingTracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return &tp.tracer
+}
+
+type recordingTracer struct {
+	embedded.Tracer
+
+	mu     sync.Mutex
+	lastID uint64
+	ended  []*recordingSpan
+}
+
+func (rt *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	rt.mu.Lock()
+	rt.lastID++
+	id := rt.lastID
+	rt.mu.Unlock()
+
+	parent := trace.SpanContextFromContext(ctx)
+	config := trace.SpanContextConfig{TraceID: parent.TraceID(), TraceFlags: trace.FlagsSampled}
+	if !parent.IsValid() {
+		binary.BigEndian.PutUint64(config.TraceID[8:], id)
+	}
+	binary.BigEndian.PutUint64(config.SpanID[:], id)
+	spanConfig := trace.NewSpanStartConfig(opts...)
+	span := &recordingSpan{
+		tracer: rt,
+		name:   name,
+		parent: parent,
+		sc:     trace.NewSpanContext(config),
+		attrs:  spanConfig.Attributes(),
+	}
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func (rt *recordingTracer) Ended() []*recordingSpan {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return append([]*recordingSpan(nil), rt.ended...)
+}
+
+type recordingSpan struct {
+	noop.Span
+
+	tracer *recordingTracer
+	name   string
+	parent trace.SpanContext
+	sc     trace.SpanContext
+	attrs  []attribute.KeyValue
+	status codes.Code
+}
+
+func (s *recordingSpan) SpanContext() trace.SpanContext { return s.sc }
+
+func (s *recordingSpan) IsRecording() bool { return true }
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.attrs = append(s.attrs, kv...)
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.status = code
+}
+
+func (s *recordingSpan) End(options ...trace.SpanEndOption) {
+	s.tracer.mu.Lock()
+	s.tracer.ended = append(s.tracer.ended, s)
+	s.tracer.mu.Unlock()
+}
+
+func TestEnableTracing(t *testing.T) {
+	tp := new(recordingTracerProvider)
+	c := tc().EnableTracing(tp)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	headers := make(http.Header)
+	resp, err := c.R().SetContext(ctx).SetSuccessResult(&headers).Get("/header")
+	assertSuccess(t, resp, err)
+	parent.End()
+
+	spans := tp.tracer.Ended()
+	tests.AssertEqual(t, 2, len(spans))
+	span := spans[0]
+	tests.AssertEqual(t, "HTTP GET", span.name)
+	tests.AssertEqual(t, parent.SpanContext().SpanID(), span.parent.SpanID())
+	tests.AssertEqual(t, parent.SpanContext().TraceID(), span.sc.TraceID())
+	attrs := attribute.NewSet(span.attrs...)
+	method, _ := attrs.Value("http.method")
+	tests.AssertEqual(t, "GET", method.AsString())
+	url, _ := attrs.Value("http.url")
+	tests.AssertEqual(t, getTestServerURL()+"/header", url.AsString())
+	code, _ := attrs.Value("http.status_code")
+	tests.AssertEqual(t, int64(http.StatusOK), code.AsInt64())
+	tests.AssertContains(t, headers.Get("Traceparent"), span.sc.SpanID().String(), true)
+
+	// each retry attempt has its own span which is the sibling of others.
+	resp, err = c.Clone().R().
+		SetRetryCount(1).
+		SetRetryFixedInterval(time.Millisecond).
+		AddRetryCondition(func(resp *Response, err error) bool {
+			return resp.GetStatusCode() == http.StatusBadRequest
+		}).
+		Get("/bad-request")
+	tests.AssertNoError(t, err)
+	spans = tp.tracer.Ended()[2:]
+	tests.AssertEqual(t, 2, len(spans))
+	for _, span := range spans {
+		tests.AssertEqual(t, false, span.parent.IsValid())
+		tests.AssertEqual(t, codes.Error, span.status)
+	}
+}
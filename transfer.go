@@ -308,9 +308,12 @@ func (t *transferWriter) writeBody(w io.Writer, dumps []*dump.Dumper) (err error
 	}()
 
 	rw := w // raw writer
+	var bodyDumpers []*dump.BodyDumper
 	for _, dump := range dumps {
 		if dump.RequestBody() {
-			w = dump.WrapRequestBodyWriter(w)
+			bd := dump.NewRequestBodyDumper(t.Header.Get("Content-Type"))
+			bodyDumpers = append(bodyDumpers, bd)
+			w = bd.WrapWriter(w)
 		}
 	}
 
@@ -325,10 +328,8 @@ func (t *transferWriter) writeBody(w io.Writer, dumps []*dump.Dumper) (err error
 				rw = &internal.FlushAfterChunkWriter{Writer: bw}
 			}
 			cw := internal.NewChunkedWriter(rw)
-			for _, dump := range dumps {
-				if dump.RequestBody() {
-					cw = dump.WrapRequestBodyWriteCloser(cw)
-				}
+			for _, bd := range bodyDumpers {
+				cw = bd.WrapChunkedWriteCloser(cw)
 			}
 			_, err = t.doBodyCopy(cw, body)
 			if err == nil {
@@ -352,6 +353,9 @@ func (t *transferWriter) writeBody(w io.Writer, dumps []*dump.Dumper) (err error
 		if err != nil {
 			return err
 		}
+		for _, bd := range bodyDumpers {
+			bd.Finish()
+		}
 		for _, dump := range dumps {
 			if dump.RequestBody() {
 				dump.DumpDefault([]byte("\r\n"))
@@ -140,6 +140,12 @@ type Transport struct {
 	autoDecodeContentType func(contentType string) bool
 	wrappedRoundTrip      http.RoundTripper
 	httpRoundTripWrappers []HttpRoundTripWrapper
+
+	metrics TransportMetrics
+
+	// maxResponseBodySize limits the size of the response body, zero means
+	// no limit.
+	maxResponseBodySize int64
 }
 
 // NewTransport is an alias of T
@@ -273,6 +279,51 @@ func (t *Transport) SetMaxConnsPerHost(max int) *Transport {
 	return t
 }
 
+// SetMaxIdleConnsPerHost set the MaxIdleConnsPerHost, which controls the
+// maximum idle (keep-alive) connections to keep per-host, it is capped by
+// MaxConnsPerHost if both are set. Zero means the default value (2).
+func (t *Transport) SetMaxIdleConnsPerHost(max int) *Transport {
+	t.MaxIdleConnsPerHost = max
+	return t
+}
+
+// SetMaxResponseBodySize set the maximum size of the response body, reading
+// the body returns ErrBodyTooLarge after n bytes are read if the body is
+// larger, which guards against the unexpected large response. Zero means no
+// limit.
+func (t *Transport) SetMaxResponseBodySize(n int64) *Transport {
+	t.maxResponseBodySize = n
+	return t
+}
+
+// ErrBodyTooLarge is returned when reading the response body which is larger
+// than the size set by SetMaxResponseBodySize.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// maxBytesBody returns ErrBodyTooLarge instead of io.EOF if there is still
+// data after the remaining bytes are read.
+type maxBytesBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *maxBytesBody) Read(p []byte) (n int, err error) {
+	if b.remaining <= 0 {
+		var buf [1]byte
+		n, err = b.ReadCloser.Read(buf[:])
+		if n > 0 {
+			return 0, ErrBodyTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err = b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return
+}
+
 // SetIdleConnTimeout set the IdleConnTimeout, which  is the maximum
 // amount of time an idle (keep-alive) connection will remain idle before
 // closing itself.
@@ -466,6 +517,52 @@ func (t *Transport) SetProxy(proxy func(*http.Request) (*url.URL, error)) *Trans
 	return t
 }
 
+// SetMetrics set the TransportMetrics which receives the connection events,
+// e.g. dial, TLS handshake and idle connection reuse.
+func (t *Transport) SetMetrics(m TransportMetrics) *Transport {
+	t.metrics = m
+	return t
+}
+
+// SetNoProxy set the hosts which bypass the proxy, each host could be a
+// hostname (optionally with port), a domain suffix with leading "." which
+// matches the subdomains (e.g. ".example.com"), an IP address, a CIDR
+// network (e.g. "10.0.0.0/8"), or "*" which matches all hosts.
+func (t *Transport) SetNoProxy(hosts ...string) *Transport {
+	t.NoProxy = hosts
+	return t
+}
+
+// bypassProxy reports whether the request to u should bypass the proxy
+// according to NoProxy.
+func (t *Transport) bypassProxy(u *url.URL) bool {
+	if len(t.NoProxy) == 0 {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	hostport := strings.ToLower(canonicalAddr(u))
+	ip := net.ParseIP(host)
+	for _, rule := range t.NoProxy {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		switch {
+		case rule == "":
+		case rule == "*":
+			return true
+		case rule == host || rule == hostport:
+			return true
+		case strings.HasPrefix(rule, "."):
+			if strings.HasSuffix(host, rule) || host == rule[1:] {
+				return true
+			}
+		case ip != nil:
+			if _, network, err := net.ParseCIDR(rule); err == nil && network.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // SetDial set the custom DialContext function, only valid for HTTP1 and HTTP2, which specifies the
 // dial function for creating unencrypted TCP connections.
 // If it is nil, then the transport dials using package net.
@@ -513,6 +610,13 @@ func (t *Transport) EnableForceHTTP1() *Transport {
 	return t
 }
 
+// DisableHTTP2 disables HTTP2 and HTTP3, only HTTP1 is used, the h2 is not
+// negotiated by ALPN, which is useful if the proxy breaks on HTTP2.
+func (t *Transport) DisableHTTP2() *Transport {
+	t.DisableHTTP3()
+	return t.EnableForceHTTP1()
+}
+
 // EnableForceHTTP2 enable force using HTTP2 for https requests
 // (disabled by default).
 func (t *Transport) EnableForceHTTP2() *Transport {
@@ -603,11 +707,30 @@ func (t *Transport) EnableHTTP3() {
 
 type wrapResponseBodyKeyType int
 
-const wrapResponseBodyKey wrapResponseBodyKeyType = iota
+const (
+	wrapResponseBodyKey wrapResponseBodyKeyType = iota
+	switchedConnKey
+)
+
+// switchedConn holds the writable body of the 101 Switching Protocols
+// response or the 2xx response of CONNECT, which may be wrapped by
+// http.Client later (e.g. with timeout).
+type switchedConn struct {
+	rwc io.ReadWriteCloser
+}
 
 type wrapResponseBodyFunc func(rc io.ReadCloser) io.ReadCloser
 
 func (t *Transport) handleResponseBody(res *http.Response, req *http.Request) {
+	if res.StatusCode == http.StatusSwitchingProtocols || bodyIsWritable(res) { // keep the body writable for the switched protocol or tunnel
+		if sc, ok := req.Context().Value(switchedConnKey).(*switchedConn); ok {
+			sc.rwc, _ = res.Body.(io.ReadWriteCloser)
+		}
+		return
+	}
+	if t.maxResponseBodySize > 0 && res.Body != nil && res.Body != NoBody {
+		res.Body = &maxBytesBody{ReadCloser: res.Body, remaining: t.maxResponseBodySize}
+	}
 	if wrap, ok := req.Context().Value(wrapResponseBodyKey).(wrapResponseBodyFunc); ok {
 		t.wrapResponseBody(res, wrap)
 	}
@@ -755,7 +878,9 @@ func (t *Transport) readBufferSize() int {
 	return 4 << 10
 }
 
-// Clone returns a deep copy of t's exported fields.
+// Clone returns a deep copy of t's exported fields, the TLSClientConfig is
+// copied into a new tls.Config (including Certificates, RootCAs and ClientCAs),
+// so mutating the TLS settings of the clone never affects t.
 func (t *Transport) Clone() *Transport {
 	tt := &Transport{
 		Headers:               t.Headers.Clone(),
@@ -765,6 +890,8 @@ func (t *Transport) Clone() *Transport {
 		autoDecodeContentType: t.autoDecodeContentType,
 		forceHttpVersion:      t.forceHttpVersion,
 		httpRoundTripWrappers: t.httpRoundTripWrappers,
+		metrics:               t.metrics,
+		maxResponseBodySize:   t.maxResponseBodySize,
 	}
 	if len(tt.httpRoundTripWrappers) > 0 { // clone transport middleware
 		fn := func(req *http.Request) (*http.Response, error) {
@@ -882,16 +1009,35 @@ func (t *Transport) checkAltSvc(req *http.Request) (resp *http.Response, err err
 			}
 		}
 		pas.Mu.Unlock()
+		if err != nil && http3.IsUnreachable(err) {
+			t.debugHTTP3Fallback(addr, err)
+			return nil, nil
+		}
 		return
 	}
 	if as := t.altSvcJar.GetAltSvc(addr); as != nil {
-		return t.roundTripAltSvc(req, as)
+		resp, err = t.roundTripAltSvc(req, as)
+		if err != nil && as.Protocol == "h3" && http3.IsUnreachable(err) {
+			// forget the alt-svc and fall back to the origin protocol.
+			t.altSvcJar.SetAltSvc(addr, nil)
+			t.debugHTTP3Fallback(addr, err)
+			return nil, nil
+		}
 	}
 	return
 }
 
+func (t *Transport) debugHTTP3Fallback(addr string, err error) {
+	if t.Debugf != nil {
+		t.Debugf("http3 of %s is unreachable, fall back to HTTP/2 or HTTP/1.1: %s", addr, err.Error())
+	}
+}
+
 // roundTrip implements a http.RoundTripper over HTTP.
 func (t *Transport) roundTrip(req *http.Request) (resp *http.Response, err error) {
+	if t.metrics != nil {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), metricsClientTrace(t.metrics)))
+	}
 	ctx := req.Context()
 	trace := httptrace.ContextClientTrace(ctx)
 
@@ -1208,7 +1354,7 @@ func resetProxyConfig() {
 func (t *Transport) connectMethodForRequest(treq *transportRequest) (cm connectMethod, err error) {
 	cm.targetScheme = treq.URL.Scheme
 	cm.targetAddr = canonicalAddr(treq.URL)
-	if t.Proxy != nil {
+	if t.Proxy != nil && !t.bypassProxy(treq.URL) {
 		cm.proxyURL, err = t.Proxy(treq.Request)
 	}
 	cm.onlyH1 = t.forceHttpVersion == h1 || requestRequiresHTTP1(treq.Request)
@@ -1272,10 +1418,15 @@ func (t *Transport) putOrCloseIdleConn(pconn *persistConn) {
 }
 
 func (t *Transport) maxIdleConnsPerHost() int {
-	if v := t.MaxIdleConnsPerHost; v != 0 {
-		return v
+	v := t.MaxIdleConnsPerHost
+	if v == 0 {
+		v = defaultMaxIdleConnsPerHost
+	}
+	// the idle connections can never exceed the total connections of the host.
+	if t.MaxConnsPerHost > 0 && v > t.MaxConnsPerHost {
+		v = t.MaxConnsPerHost
 	}
-	return defaultMaxIdleConnsPerHost
+	return v
 }
 
 // tryPutIdleConn adds pconn to the list of idle persistent connections awaiting
@@ -2850,7 +3001,7 @@ func (pc *persistConn) readResponse(rc requestAndChan, trace *httptrace.ClientTr
 		}
 		break
 	}
-	if isProtocolSwitch(resp) {
+	if isProtocolSwitch(resp) || isTunnelEstablished(rc.req, resp) {
 		resp.Body = newReadWriteCloserBody(pc.br, pc.conn)
 	}
 
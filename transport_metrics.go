@@ -0,0 +1,55 @@
+package req
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// TransportMetrics is the hook interface which receives the connection
+// events of the Transport, can be used to export the metrics of the
+// connection pool. Its methods may be called concurrently.
+type TransportMetrics interface {
+	// OnConnectDone is called when a new connection's dial completes,
+	// err is nil if the dial succeeded.
+	OnConnectDone(network, addr string, err error, duration time.Duration)
+	// OnTLSHandshakeDone is called after the TLS handshake with either
+	// the successful handshake's connection state, or a non-nil error
+	// on handshake failure.
+	OnTLSHandshakeDone(state tls.ConnectionState, err error)
+	// OnIdleConnReused is called when an idle connection in the pool is
+	// reused for the request, addr is the remote address of the connection.
+	OnIdleConnReused(addr string)
+}
+
+// metricsClientTrace creates the httptrace.ClientTrace which reports the
+// connection events of a request to m.
+func metricsClientTrace(m TransportMetrics) *httptrace.ClientTrace {
+	var mu sync.Mutex
+	connectStart := make(map[string]time.Time)
+	return &httptrace.ClientTrace{
+		ConnectStart: func(network, addr string) {
+			mu.Lock()
+			connectStart[network+addr] = time.Now()
+			mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			mu.Lock()
+			start, ok := connectStart[network+addr]
+			delete(connectStart, network+addr)
+			mu.Unlock()
+			var duration time.Duration
+			if ok {
+				duration = time.Since(start)
+			}
+			m.OnConnectDone(network, addr, err, duration)
+		},
+		TLSHandshakeDone: m.OnTLSHandshakeDone,
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused && info.Conn != nil {
+				m.OnIdleConnReused(info.Conn.RemoteAddr().String())
+			}
+		},
+	}
+}
@@ -0,0 +1,276 @@
+package req
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// The message types defined in RFC 6455, which are compatible with
+// gorilla/websocket.
+const (
+	// TextMessage denotes a text data message, the payload is
+	// interpreted as UTF-8 encoded text.
+	TextMessage = 1
+	// BinaryMessage denotes a binary data message.
+	BinaryMessage = 2
+	// CloseMessage denotes a close control message.
+	CloseMessage = 8
+	// PingMessage denotes a ping control message.
+	PingMessage = 9
+	// PongMessage denotes a pong control message.
+	PongMessage = 10
+)
+
+const (
+	wsContinuationFrame = 0
+	wsMaxControlPayload = 125
+	wsDefaultReadLimit  = 32 << 20
+	wsAcceptGUID        = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// ErrWebSocketClosed is returned by WSConn.ReadMessage after the close
+// message is received.
+var ErrWebSocketClosed = errors.New("websocket: connection closed")
+
+// ErrWebSocketReadLimit is returned by WSConn.ReadMessage if the size of the
+// received frame or message exceeds the read limit, see WSConn.SetReadLimit.
+var ErrWebSocketReadLimit = errors.New("websocket: read limit exceeded")
+
+// WSConn is a minimal client-side WebSocket connection, it is safe to call
+// WriteMessage concurrently with ReadMessage, but ReadMessage should not be
+// called concurrently.
+type WSConn struct {
+	rwc  io.ReadWriteCloser
+	br   *bufio.Reader
+	body io.Closer // the upgrade response body which wraps rwc
+
+	readLimit int64
+
+	writeMu sync.Mutex
+	closed  bool
+}
+
+// SetReadLimit set the max size in bytes of the message read by ReadMessage
+// (including all its fragments), ReadMessage returns ErrWebSocketReadLimit
+// if a frame or message exceeds it. The default limit (used if limit <= 0)
+// is 32 MiB.
+func (c *WSConn) SetReadLimit(limit int64) {
+	c.readLimit = limit
+}
+
+func (c *WSConn) getReadLimit() int64 {
+	if c.readLimit <= 0 {
+		return wsDefaultReadLimit
+	}
+	return c.readLimit
+}
+
+// ReadMessage reads the next data message, messageType is TextMessage or
+// BinaryMessage. Ping messages are answered with pong automatically, and
+// ErrWebSocketClosed is returned if the close message is received.
+func (c *WSConn) ReadMessage() (messageType int, p []byte, err error) {
+	for {
+		fin, opcode, payload, err := c.readFrame(c.getReadLimit() - int64(len(p)))
+		if err != nil {
+			return 0, nil, err
+		}
+		switch opcode {
+		case PingMessage:
+			if err = c.WriteMessage(PongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case PongMessage:
+			continue
+		case CloseMessage:
+			c.WriteMessage(CloseMessage, payload)
+			return 0, nil, ErrWebSocketClosed
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, errors.New("websocket: data frame received before the previous message is finished")
+			}
+			messageType = opcode
+		case wsContinuationFrame:
+			if messageType == 0 {
+				return 0, nil, errors.New("websocket: unexpected continuation frame")
+			}
+		default:
+			return 0, nil, fmt.Errorf("websocket: unknown opcode %d", opcode)
+		}
+		p = append(p, payload...)
+		if fin {
+			return messageType, p, nil
+		}
+	}
+}
+
+// readFrame reads the next frame, and returns ErrWebSocketReadLimit before
+// reading the payload if its length exceeds limit.
+func (c *WSConn) readFrame(limit int64) (fin bool, opcode int, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(c.br, head[:]); err != nil {
+		return
+	}
+	fin = head[0]&0x80 != 0
+	opcode = int(head[0] & 0x0f)
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return
+		}
+	}
+	if opcode >= CloseMessage && (length > wsMaxControlPayload || !fin) {
+		err = errors.New("websocket: invalid control frame")
+		return
+	}
+	if length > uint64(limit) && opcode < CloseMessage {
+		err = ErrWebSocketReadLimit
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		maskBytes(mask, payload)
+	}
+	return
+}
+
+// WriteMessage writes a message with the given messageType and payload,
+// the frame is masked as required for the client.
+func (c *WSConn) WriteMessage(messageType int, data []byte) error {
+	if messageType >= CloseMessage && len(data) > wsMaxControlPayload {
+		return errors.New("websocket: control message payload is too large")
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return ErrWebSocketClosed
+	}
+	frame := make([]byte, 0, 14+len(data))
+	frame = append(frame, 0x80|byte(messageType))
+	switch n := len(data); {
+	case n <= 125:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(rand.Reader, mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	start := len(frame)
+	frame = append(frame, data...)
+	maskBytes(mask, frame[start:])
+	if _, err := c.rwc.Write(frame); err != nil {
+		return err
+	}
+	if messageType == CloseMessage {
+		c.closed = true
+	}
+	return nil
+}
+
+// Close sends the close message if it has not been sent, and closes the
+// underlying connection.
+func (c *WSConn) Close() error {
+	c.WriteMessage(CloseMessage, []byte{0x03, 0xe8}) // 1000: normal closure
+	if c.body != nil {
+		return c.body.Close()
+	}
+	return c.rwc.Close()
+}
+
+func maskBytes(mask [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= mask[i%4]
+	}
+}
+
+func webSocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// WebSocket sends the WebSocket upgrade handshake with GET method, and
+// returns the WebSocket connection if the server switches the protocol,
+// the url could be either "ws://", "wss://", "http://" or "https://". The
+// handshake goes through the middlewares and the dump like other requests,
+// the returned Response is the upgrade response, its body must not be read.
+//
+// For Example:
+//
+//	conn, resp, err := client.R().SetURL("wss://example.com/ws").WebSocket()
+//	if err != nil {
+//		return err
+//	}
+//	defer conn.Close()
+//	err = conn.WriteMessage(req.TextMessage, []byte("hello"))
+func (r *Request) WebSocket() (*WSConn, *Response, error) {
+	if u, ok := strings.CutPrefix(r.RawURL, "ws://"); ok {
+		r.RawURL = "http://" + u
+	} else if u, ok := strings.CutPrefix(r.RawURL, "wss://"); ok {
+		r.RawURL = "https://" + u
+	}
+	var nonce [16]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	r.SetHeader("Connection", "Upgrade").
+		SetHeader("Upgrade", "websocket").
+		SetHeader("Sec-WebSocket-Version", "13").
+		SetHeader("Sec-WebSocket-Key", key)
+	sc := &switchedConn{}
+	r.SetContext(context.WithValue(r.Context(), switchedConnKey, sc))
+	resp, err := r.Send(http.MethodGet, "")
+	if err != nil {
+		return nil, resp, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, resp, fmt.Errorf("websocket: bad handshake: %s", resp.Status)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") ||
+		resp.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key) {
+		resp.Body.Close()
+		return nil, resp, errors.New("websocket: bad handshake: invalid upgrade response headers")
+	}
+	if sc.rwc == nil {
+		resp.Body.Close()
+		return nil, resp, errors.New("websocket: the response body is not writable")
+	}
+	return &WSConn{rwc: sc.rwc, br: bufio.NewReader(sc.rwc), body: resp.Body}, resp, nil
+}
@@ -0,0 +1,112 @@
+package req
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/imroc/req/v3/internal/tests"
+)
+
+// createWebSocketEchoServer creates a server which echoes the messages
+// back, and sends a ping before each echo.
+func createWebSocketEchoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Sec-WebSocket-Key")
+		if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		brw.WriteString("Sec-WebSocket-Accept: " + webSocketAccept(key) + "\r\n\r\n")
+		brw.Flush()
+		ws := &WSConn{rwc: conn, br: bufio.NewReader(brw)}
+		writeFrame := func(opcode int, payload []byte) { // server frames are not masked
+			conn.Write(append([]byte{0x80 | byte(opcode), byte(len(payload))}, payload...))
+		}
+		for {
+			_, opcode, payload, err := ws.readFrame(wsDefaultReadLimit)
+			if err != nil {
+				return
+			}
+			switch opcode {
+			case CloseMessage:
+				writeFrame(CloseMessage, payload)
+				return
+			case PongMessage:
+				continue
+			}
+			writeFrame(PingMessage, []byte("ping"))
+			writeFrame(opcode, payload)
+		}
+	}))
+}
+
+func TestWebSocket(t *testing.T) {
+	server := createWebSocketEchoServer()
+	defer server.Close()
+
+	buf := new(bytes.Buffer)
+	conn, resp, err := C().R().EnableDumpTo(buf).SetURL("ws" + strings.TrimPrefix(server.URL, "http")).WebSocket()
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	tests.AssertContains(t, buf.String(), "101 switching protocols", true)
+
+	for _, msg := range []string{"hello", "world"} {
+		tests.AssertNoError(t, conn.WriteMessage(TextMessage, []byte(msg)))
+		messageType, p, err := conn.ReadMessage()
+		tests.AssertNoError(t, err)
+		tests.AssertEqual(t, TextMessage, messageType)
+		tests.AssertEqual(t, msg, string(p))
+	}
+	data := bytes.Repeat([]byte{0xff}, 100)
+	tests.AssertNoError(t, conn.WriteMessage(BinaryMessage, data))
+	messageType, p, err := conn.ReadMessage()
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, BinaryMessage, messageType)
+	tests.AssertEqual(t, data, p)
+
+	tests.AssertNoError(t, conn.WriteMessage(CloseMessage, nil))
+	tests.AssertEqual(t, ErrWebSocketClosed, conn.WriteMessage(TextMessage, data))
+	conn.Close()
+}
+
+func TestWebSocketBadHandshake(t *testing.T) {
+	_, resp, err := tc().R().WebSocket()
+	tests.AssertErrorContains(t, err, "websocket: bad handshake")
+	tests.AssertEqual(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestWebSocketReadLimit(t *testing.T) {
+	// the frame header claims a huge length without the payload.
+	frame := []byte{0x82, 127, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	conn := &WSConn{br: bufio.NewReader(bytes.NewReader(frame))}
+	_, _, err := conn.ReadMessage()
+	tests.AssertEqual(t, ErrWebSocketReadLimit, err)
+
+	// the limit applies to the whole fragmented message.
+	var msg []byte
+	msg = append(msg, 0x01, 3) // text frame without fin
+	msg = append(msg, "abc"...)
+	msg = append(msg, 0x80, 3) // final continuation frame
+	msg = append(msg, "def"...)
+	conn = &WSConn{br: bufio.NewReader(bytes.NewReader(msg))}
+	conn.SetReadLimit(6)
+	messageType, p, err := conn.ReadMessage()
+	tests.AssertNoError(t, err)
+	tests.AssertEqual(t, TextMessage, messageType)
+	tests.AssertEqual(t, "abcdef", string(p))
+
+	conn = &WSConn{br: bufio.NewReader(bytes.NewReader(msg))}
+	conn.SetReadLimit(5)
+	_, _, err = conn.ReadMessage()
+	tests.AssertEqual(t, ErrWebSocketReadLimit, err)
+}